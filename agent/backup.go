@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Directory the working tree is copied to before the first mutation (empty disables backups)
+var backupDir = ""
+
+// Directories left out of backups, both for speed and because restoring them is never wanted
+var backupSkipDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+}
+
+// Backup state for the current session
+var (
+	backupFiles    map[string]bool // Relative paths of the backed-up files, nil until a backup is taken
+	backupUnmerged []string        // Files git reported as unmerged when the backup was taken
+	backupMu       sync.Mutex
+)
+
+// ensureBackup copies the working tree to backupDir the first time it is called
+func ensureBackup(ctx context.Context) error {
+	backupMu.Lock()
+	defer backupMu.Unlock()
+
+	if backupDir == "" || backupFiles != nil {
+		return nil
+	}
+
+	files := make(map[string]bool)
+	err := walkBackupTree(".", func(path string) error {
+		files[path] = true
+		return copyFile(path, filepath.Join(backupDir, path))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to back up working tree to %s: %w", backupDir, err)
+	}
+
+	// Remember the conflicted files so restoring can mark them unmerged in the index again
+	backupUnmerged, _ = ListUnmergedFiles(ctx)
+	backupFiles = files
+	return nil
+}
+
+// restoreBackup copies every backed-up file back into the working tree and removes files
+// created since the backup. Files that were unmerged at the time of the backup but have been
+// staged since are marked unmerged again. Returns the number of files restored, the paths
+// removed, and the paths whose conflicts were re-created in the index.
+func restoreBackup(ctx context.Context) (int, []string, []string, error) {
+	backupMu.Lock()
+	defer backupMu.Unlock()
+
+	if backupDir == "" {
+		return 0, nil, nil, fmt.Errorf("backups are disabled; run GitSynth with -backup-dir to enable them")
+	}
+	if backupFiles == nil {
+		return 0, nil, nil, fmt.Errorf("no backup has been taken yet because nothing has been modified")
+	}
+
+	var removed []string
+	err := walkBackupTree(".", func(path string) error {
+		if backupFiles[path] {
+			return nil
+		}
+		removed = append(removed, path)
+		return os.Remove(path)
+	})
+	if err != nil {
+		return 0, removed, nil, fmt.Errorf("failed to remove files created since the backup: %w", err)
+	}
+
+	// Re-create the conflicts of files staged since the backup; this rewrites them with conflict
+	// markers, which the backed-up copies then overwrite
+	reconflicted, err := reconflictFiles(ctx, backupUnmerged)
+	if err != nil {
+		return 0, removed, reconflicted, err
+	}
+
+	for path := range backupFiles {
+		if err := copyFile(filepath.Join(backupDir, path), path); err != nil {
+			return 0, removed, reconflicted, fmt.Errorf("failed to restore %s: %w", path, err)
+		}
+	}
+	return len(backupFiles), removed, reconflicted, nil
+}
+
+// reconflictFiles marks the given files unmerged in the index again, using the resolve-undo
+// information git keeps when a conflicted file is staged. Files still unmerged are left alone.
+func reconflictFiles(ctx context.Context, paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	stillUnmerged, err := ListUnmergedFiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unmerged files: %w", err)
+	}
+	unmerged := make(map[string]bool)
+	for _, path := range stillUnmerged {
+		unmerged[path] = true
+	}
+
+	var reconflicted []string
+	for _, path := range paths {
+		if unmerged[path] {
+			continue
+		}
+		if _, err := ExecuteGitCommand(ctx, "checkout", "-m", "--", path); err != nil {
+			return reconflicted, fmt.Errorf("failed to mark %s as unmerged again: %w", path, err)
+		}
+		reconflicted = append(reconflicted, path)
+	}
+	return reconflicted, nil
+}
+
+// walkBackupTree calls fn with the relative path of every regular file under root,
+// skipping backupSkipDirs and the backup directory itself
+func walkBackupTree(root string, fn func(path string) error) error {
+	absBackup, err := filepath.Abs(backupDir)
+	if err != nil {
+		return err
+	}
+
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if abs, err := filepath.Abs(path); err == nil && abs == absBackup {
+				return filepath.SkipDir
+			}
+			if path != root && backupSkipDirs[entry.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		return fn(path)
+	})
+}
+
+// copyFile copies a regular file, creating parent directories and preserving its permissions
+func copyFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
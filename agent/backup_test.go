@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRestoreAllRecreatesConflicts(t *testing.T) {
+	initTestRepo(t)
+	mergeWithConflicts(t,
+		map[string]string{"f.txt": "base\n", "g.txt": "base\n"},
+		map[string]string{"f.txt": "ours\n", "g.txt": "ours\n"},
+		map[string]string{"f.txt": "theirs\n", "g.txt": "theirs\n"},
+	)
+	conflicted := readTempFile(t, "f.txt")
+
+	backupDir = t.TempDir()
+	t.Cleanup(func() {
+		backupDir = ""
+		backupFiles = nil
+		backupUnmerged = nil
+	})
+	if err := ensureBackup(context.Background()); err != nil {
+		t.Fatalf("ensureBackup() error = %v", err)
+	}
+
+	// Resolve and stage f.txt, edit g.txt without staging it, and create a new file
+	if err := os.WriteFile("f.txt", []byte("resolved\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, "add", "f.txt")
+	if err := os.WriteFile("g.txt", []byte("half done\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("new.txt", []byte("new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := RestoreAll(context.Background(), json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("RestoreAll() error = %v", err)
+	}
+	if !strings.Contains(result, "Marked 1 file(s) staged since the backup as unmerged again:\n  f.txt") {
+		t.Errorf("RestoreAll() = %q, want it to report f.txt as unmerged again", result)
+	}
+
+	if got := readTempFile(t, "f.txt"); got != conflicted {
+		t.Errorf("f.txt = %q, want the conflicted content %q", got, conflicted)
+	}
+	if got := readTempFile(t, "g.txt"); !strings.Contains(got, "<<<<<<<") {
+		t.Errorf("g.txt = %q, want the conflicted content restored", got)
+	}
+	if _, err := os.Stat("new.txt"); !os.IsNotExist(err) {
+		t.Errorf("new.txt still exists, want it removed")
+	}
+
+	unmerged, err := ListUnmergedFiles(context.Background())
+	if err != nil {
+		t.Fatalf("ListUnmergedFiles() error = %v", err)
+	}
+	if want := []string{"f.txt", "g.txt"}; !reflect.DeepEqual(unmerged, want) {
+		t.Errorf("unmerged files = %v, want %v", unmerged, want)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Number of leading bytes inspected to decide whether a file is binary, as git does
+const binarySampleSize = 8000
+
+// Common binary file signatures
+var binaryFileSignatures = [][]byte{
+	{0x7F, 0x45, 0x4C, 0x46}, // ELF
+	{0x4D, 0x5A},             // PE/DOS
+	{0xFE, 0xED, 0xFA, 0xCE}, // Mach-O
+	{0x50, 0x4B, 0x03, 0x04}, // ZIP
+	{0x1F, 0x8B},             // gzip
+	{0x89, 0x50, 0x4E, 0x47}, // PNG
+	{0xFF, 0xD8, 0xFF},       // JPEG
+	{0x47, 0x49, 0x46, 0x38}, // GIF
+	{0x25, 0x50, 0x44, 0x46}, // PDF
+}
+
+// Extensions of files that are binary in practice, skipped without reading them
+var binaryExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".ico": true, ".webp": true,
+	".pdf": true, ".zip": true, ".gz": true, ".tgz": true, ".jar": true, ".7z": true,
+	".exe": true, ".dll": true, ".so": true, ".dylib": true, ".a": true, ".o": true,
+	".class": true, ".pyc": true, ".wasm": true, ".woff": true, ".woff2": true, ".ttf": true,
+	".mp3": true, ".mp4": true, ".mov": true, ".sqlite": true,
+}
+
+// hasBinaryExtension reports whether a path's extension marks it as binary
+func hasBinaryExtension(path string) bool {
+	return binaryExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// isBinaryContent guesses whether the start of a file is binary: it has a known binary signature,
+// contains a NUL byte, or more than a tenth of it is not valid UTF-8
+func isBinaryContent(sample []byte) bool {
+	if len(sample) > binarySampleSize {
+		sample = sample[:binarySampleSize]
+	}
+
+	for _, sig := range binaryFileSignatures {
+		if bytes.HasPrefix(sample, sig) {
+			return true
+		}
+	}
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return true
+	}
+
+	// A few invalid bytes are tolerated so Latin-1 text still counts as text
+	invalid := 0
+	for i := 0; i < len(sample); {
+		r, size := utf8.DecodeRune(sample[i:])
+		if r == utf8.RuneError && size == 1 {
+			// A rune cut off by the end of the sample is not invalid
+			if len(sample)-i < utf8.UTFMax && !utf8.FullRune(sample[i:]) {
+				break
+			}
+			invalid++
+		}
+		i += size
+	}
+	return invalid > len(sample)/10
+}
+
+// isBinaryFile checks if a file is likely binary by looking at its first few bytes
+func isBinaryFile(file *os.File) bool {
+	buf := make([]byte, binarySampleSize)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return true // Assume binary on error
+	}
+	return isBinaryContent(buf[:n])
+}
+
+// checkNotBinary returns an error if a file's content looks binary, so tools neither flood the
+// conversation with it nor corrupt it by editing it as lines of text
+func checkNotBinary(path string, content []byte) error {
+	if isBinaryContent(content) {
+		return fmt.Errorf("%s looks like a binary file, so it cannot be viewed or edited as text; resolve it with accept_version or delete_file", path)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Number of lines around a conflict region that strict region mode still allows editing
+const conflictRegionMargin = 3
+
+// When enabled, edit_file_line refuses to modify lines outside the original conflict regions
+var strictRegions = false
+
+// lineRange is an inclusive, 1-based range of lines
+type lineRange struct {
+	Start int
+	End   int
+}
+
+// Conflict regions per file, snapshotted before the agent starts and shifted as edits change line numbers
+var (
+	conflictRegions   = make(map[string][]lineRange)
+	conflictRegionsMu sync.Mutex
+)
+
+// snapshotConflictRegions records the conflict regions of every unmerged file
+func snapshotConflictRegions(ctx context.Context) error {
+	files, err := ListUnmergedFiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	conflictRegionsMu.Lock()
+	defer conflictRegionsMu.Unlock()
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			// Deleted on one side; there is nothing to edit line by line
+			continue
+		}
+
+		chunks, err := FindConflictChunks(string(content))
+		if err != nil {
+			return fmt.Errorf("failed to parse conflicts in %s: %w", file, err)
+		}
+
+		var regions []lineRange
+		for _, chunk := range chunks {
+			regions = append(regions, lineRange{Start: chunk.StartLine, End: chunk.EndLine})
+		}
+		conflictRegions[filepath.Clean(file)] = regions
+	}
+	return nil
+}
+
+// shiftConflictRegions updates a file's regions after lines start..end were replaced by newLineCount lines.
+// Regions touching the edit grow to cover the replacement, and regions below it move by the line delta.
+func shiftConflictRegions(path string, start, end, newLineCount int) {
+	conflictRegionsMu.Lock()
+	defer conflictRegionsMu.Unlock()
+
+	path = filepath.Clean(path)
+	regions := conflictRegions[path]
+	delta := newLineCount - (end - start + 1)
+	newEnd := start + newLineCount - 1
+
+	for i, region := range regions {
+		switch {
+		case region.Start > end:
+			regions[i].Start += delta
+			regions[i].End += delta
+		case region.End >= start:
+			regions[i].Start = min(region.Start, start)
+			if region.End > end {
+				regions[i].End = region.End + delta
+			} else {
+				regions[i].End = max(newEnd, regions[i].Start)
+			}
+		}
+	}
+}
+
+// checkConflictRegion returns an error if strict region mode is enabled and lines start..end
+// fall outside every known conflict region of the file (allowing conflictRegionMargin lines of context)
+func checkConflictRegion(path string, start, end int) error {
+	if !strictRegions {
+		return nil
+	}
+
+	conflictRegionsMu.Lock()
+	defer conflictRegionsMu.Unlock()
+
+	for _, region := range conflictRegions[filepath.Clean(path)] {
+		if start >= region.Start-conflictRegionMargin && end <= region.End+conflictRegionMargin {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("refusing to edit lines %d-%d of %s: they are outside every conflict region (strict region mode is enabled). "+
+		"Only make conflict-related changes, or set allow_outside_conflicts if this edit is truly required", start, end, path)
+}
+
+// resetConflictRegions recomputes a file's regions from its current conflict markers, e.g. after it
+// was restored to an earlier version. Files without tracked regions are left alone.
+func resetConflictRegions(path string) {
+	conflictRegionsMu.Lock()
+	defer conflictRegionsMu.Unlock()
+
+	path = filepath.Clean(path)
+	if _, ok := conflictRegions[path]; !ok {
+		return
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	chunks, err := FindConflictChunks(string(content))
+	if err != nil {
+		return
+	}
+
+	var regions []lineRange
+	for _, chunk := range chunks {
+		regions = append(regions, lineRange{Start: chunk.StartLine, End: chunk.EndLine})
+	}
+	conflictRegions[path] = regions
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestStrictRegionsEditFileLine(t *testing.T) {
+	initTestRepo(t)
+	lines := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	mergeWithConflicts(t,
+		map[string]string{"f.txt": lines + "base\n"},
+		map[string]string{"f.txt": lines + "ours\n"},
+		map[string]string{"f.txt": lines + "theirs\n"},
+	)
+	t.Cleanup(func() {
+		strictRegions = false
+		conflictRegions = make(map[string][]lineRange)
+	})
+	if err := snapshotConflictRegions(context.Background()); err != nil {
+		t.Fatalf("snapshotConflictRegions() error = %v", err)
+	}
+
+	edit := func(line int, allowOutside bool) error {
+		input, _ := json.Marshal(EditFileLineInput{Path: "f.txt", StartLine: line, NewContent: readLine(t, line), AllowOutsideConflicts: allowOutside})
+		_, err := EditFileLine(context.Background(), input)
+		return err
+	}
+
+	// The conflict spans lines 13-17, so line 10 is within the margin and line 2 is outside
+	strictRegions = true
+	if err := edit(2, false); err == nil || !strings.Contains(err.Error(), "outside every conflict region") {
+		t.Errorf("edit outside the conflict in strict mode: error = %v, want a refusal", err)
+	}
+	if err := edit(10, false); err != nil {
+		t.Errorf("edit within the margin in strict mode: error = %v", err)
+	}
+	if err := edit(15, false); err != nil {
+		t.Errorf("edit inside the conflict in strict mode: error = %v", err)
+	}
+	if err := edit(2, true); err != nil {
+		t.Errorf("edit outside the conflict with the override: error = %v", err)
+	}
+
+	strictRegions = false
+	if err := edit(2, false); err != nil {
+		t.Errorf("edit outside the conflict without strict mode: error = %v", err)
+	}
+}
+
+// readLine returns a 1-based line of f.txt, so edits can rewrite a line without changing it
+func readLine(t *testing.T, line int) string {
+	t.Helper()
+	return strings.Split(readTempFile(t, "f.txt"), "\n")[line-1]
+}
+
+func TestShiftConflictRegions(t *testing.T) {
+	t.Cleanup(func() { conflictRegions = make(map[string][]lineRange) })
+	conflictRegions = map[string][]lineRange{"f.txt": {{Start: 5, End: 9}, {Start: 20, End: 24}}}
+
+	// Replacing lines 5-9 with two lines shrinks the first region and moves the second up by 3
+	shiftConflictRegions("f.txt", 5, 9, 2)
+	want := []lineRange{{Start: 5, End: 6}, {Start: 17, End: 21}}
+	for i, region := range conflictRegions["f.txt"] {
+		if region != want[i] {
+			t.Errorf("region %d = %+v, want %+v", i, region, want[i])
+		}
+	}
+}
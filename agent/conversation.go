@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// Number of trailing messages that are never trimmed when shrinking a conversation
+const recentMessagesToKeep = 6
+
+// Default estimated token count past which the conversation is proactively compacted
+const defaultCompactThreshold = 120000
+
+// Rough number of characters per token used for estimating conversation size
+const charsPerToken = 4
+
+// Maximum length of the synopsis kept for each compacted tool result
+const maxSynopsisLength = 200
+
+// Placeholder that replaces tool results dropped to fit the context window
+const elidedToolResult = "[Tool result elided to fit the context window. Re-run the tool if you need this output again.]"
+
+// Prefix marking a tool result that has been compacted into a synopsis
+const compactedToolResultPrefix = "[Compacted tool result"
+
+// validateCompactSettings checks the compaction flags and config. Negative values are mistakes,
+// and at least the latest message must be kept, or the results of the tool calls the agent has
+// just made would be compacted before it sees them. Zero config values mean the defaults.
+func validateCompactSettings(threshold, keep int, config *Config) error {
+	if threshold < 0 || config.CompactThreshold < 0 {
+		return fmt.Errorf("the compact threshold must be 0 or greater")
+	}
+	if keep < 1 || config.CompactKeep < 0 {
+		return fmt.Errorf("the number of messages to keep when compacting must be 1 or greater")
+	}
+	return nil
+}
+
+// isContextLengthError checks if an error is the API rejecting a request for exceeding the context window
+func isContextLengthError(err error) bool {
+	var apiErr *anthropic.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+
+	raw := strings.ToLower(apiErr.RawJSON())
+	return strings.Contains(raw, "prompt is too long") ||
+		strings.Contains(raw, "context length") ||
+		strings.Contains(raw, "context window")
+}
+
+// estimateTokens gives a rough token count for a conversation based on its serialized size
+func estimateTokens(conversation []anthropic.MessageParam) int {
+	data, err := json.Marshal(conversation)
+	if err != nil {
+		return 0
+	}
+	return len(data) / charsPerToken
+}
+
+// shrinkConversation replaces the contents of older tool results with a short placeholder.
+// Returns the shrunk conversation and whether anything was trimmed.
+func shrinkConversation(conversation []anthropic.MessageParam, keepRecent int) ([]anthropic.MessageParam, bool) {
+	return rewriteToolResults(conversation, keepRecent, func(string) string {
+		return elidedToolResult
+	})
+}
+
+// compactConversation replaces the contents of older tool results with a one-line synopsis.
+// Returns the compacted conversation and whether anything was compacted.
+func compactConversation(conversation []anthropic.MessageParam, keepRecent int) ([]anthropic.MessageParam, bool) {
+	return rewriteToolResults(conversation, keepRecent, synopsize)
+}
+
+// rewriteToolResults replaces the text of each older tool result using the rewrite function.
+// The first message (the system prompt and task) and the last keepRecent messages are left untouched.
+// Tool result blocks are kept in place so that every tool_use still has a matching tool_result.
+func rewriteToolResults(conversation []anthropic.MessageParam, keepRecent int, rewrite func(string) string) ([]anthropic.MessageParam, bool) {
+	shrunk := make([]anthropic.MessageParam, len(conversation))
+	copy(shrunk, conversation)
+
+	trimmed := false
+	for i := 1; i < len(shrunk)-keepRecent; i++ {
+		message := shrunk[i]
+		if message.Role != anthropic.MessageParamRoleUser {
+			continue
+		}
+
+		blocks := make([]anthropic.ContentBlockParamUnion, len(message.Content))
+		for j, block := range message.Content {
+			blocks[j] = block
+			result := block.OfRequestToolResultBlock
+			if result == nil {
+				continue
+			}
+
+			text := toolResultText(result)
+			if text == elidedToolResult || strings.HasPrefix(text, compactedToolResultPrefix) {
+				continue
+			}
+
+			isError := result.IsError.Or(false)
+			blocks[j] = anthropic.NewToolResultBlock(result.ToolUseID, rewrite(text), isError)
+			trimmed = true
+		}
+		shrunk[i] = anthropic.NewUserMessage(blocks...)
+	}
+
+	return shrunk, trimmed
+}
+
+// toolResultText joins the text content of a tool result block
+func toolResultText(result *anthropic.ToolResultBlockParam) string {
+	var parts []string
+	for _, content := range result.Content {
+		if content.OfRequestTextBlock != nil {
+			parts = append(parts, content.OfRequestTextBlock.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// synopsize reduces a tool result to its first non-empty line, truncated, plus its original size
+func synopsize(text string) string {
+	firstLine := ""
+	for _, line := range strings.Split(text, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			firstLine = trimmed
+			break
+		}
+	}
+	if len(firstLine) > maxSynopsisLength {
+		// Cut on a rune boundary so multi-byte characters are not split
+		cut := maxSynopsisLength - 3
+		for cut > 0 && !utf8.RuneStart(firstLine[cut]) {
+			cut--
+		}
+		firstLine = firstLine[:cut] + "..."
+	}
+
+	return fmt.Sprintf("%s, originally %d chars. Re-run the tool if you need the full output.] %s",
+		compactedToolResultPrefix, len(text), firstLine)
+}
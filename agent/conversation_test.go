@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+func TestCompactConversationPreservesRequiredMessages(t *testing.T) {
+	conversation := conversationWithToolResult("old output\nmore lines")
+	conversation = append(conversation,
+		anthropic.NewAssistantMessage(anthropic.ContentBlockParamUnion{OfRequestToolUseBlock: &anthropic.ToolUseBlockParam{
+			ID: "tool_2", Name: "view_file", Input: map[string]any{},
+		}}),
+		anthropic.NewUserMessage(anthropic.NewToolResultBlock("tool_2", "recent output", false)),
+	)
+
+	compacted, ok := compactConversation(conversation, 2)
+	if !ok {
+		t.Fatal("compactConversation() compacted nothing, want the old tool result compacted")
+	}
+	if len(compacted) != len(conversation) {
+		t.Fatalf("compacted conversation has %d messages, want %d", len(compacted), len(conversation))
+	}
+	if got := compacted[0].Content[0].OfRequestTextBlock.Text; got != "prompt" {
+		t.Errorf("first message = %q, want the prompt untouched", got)
+	}
+
+	old := compacted[2].Content[0].OfRequestToolResultBlock
+	if old.ToolUseID != "tool_1" {
+		t.Errorf("compacted tool result answers %q, want tool_1", old.ToolUseID)
+	}
+	if text := toolResultText(old); !strings.HasPrefix(text, compactedToolResultPrefix) || !strings.HasSuffix(text, "old output") {
+		t.Errorf("compacted tool result = %q, want a synopsis of its first line", text)
+	}
+	if text := toolResultText(compacted[6].Content[0].OfRequestToolResultBlock); text != "recent output" {
+		t.Errorf("recent tool result = %q, want it kept intact", text)
+	}
+
+	// Compacting again finds nothing new to compact
+	if _, ok := compactConversation(compacted, 2); ok {
+		t.Error("compactConversation() compacted an already compacted conversation")
+	}
+}
+
+func TestSynopsizeTruncatesOnRuneBoundary(t *testing.T) {
+	line := strings.Repeat("é", maxSynopsisLength)
+	synopsis := synopsize(line + "\nsecond line")
+	if !utf8.ValidString(synopsis) {
+		t.Fatalf("synopsize() = %q, want valid UTF-8", synopsis)
+	}
+	if !strings.HasSuffix(synopsis, "...") {
+		t.Errorf("synopsize() = %q, want the long line truncated", synopsis)
+	}
+	if strings.Contains(synopsis, "second line") {
+		t.Errorf("synopsize() = %q, want only the first line", synopsis)
+	}
+}
+
+func TestRunCompactsPastThreshold(t *testing.T) {
+	agent, api := newStubAgent(t,
+		stubResponse{tool: "long_output"},
+		stubResponse{tool: "long_output"},
+		stubResponse{text: "[ALL DONE]"},
+	)
+	agent.compactThreshold = 2000
+	agent.compactKeep = 2
+	agent.tools = []ToolDefinition{{
+		Name:        "long_output",
+		Description: "Returns a long output",
+		InputSchema: GenerateSchema[struct{}](),
+		Function: func(context.Context, json.RawMessage) (string, error) {
+			return "first line of a long output\n" + strings.Repeat("x", 10000), nil
+		},
+	}}
+
+	if err := agent.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := api.requestCount(); got != 3 {
+		t.Fatalf("requests = %d, want 3", got)
+	}
+
+	// The last request compacted the first tool result but kept the prompt and the latest result
+	last := api.requests[2]
+	if !strings.Contains(last, "Compacted tool result") || !strings.Contains(last, "first line of a long output") {
+		t.Errorf("last request does not contain a synopsis of the first tool result")
+	}
+	if strings.Count(last, strings.Repeat("x", 10000)) != 1 {
+		t.Errorf("last request should keep exactly one full tool result")
+	}
+	if !strings.Contains(last, "GitSynth") {
+		t.Errorf("last request lost the system prompt")
+	}
+	if strings.Contains(api.requests[1], "Compacted tool result") {
+		t.Errorf("second request was compacted although only one tool result was outside the kept messages")
+	}
+}
+
+func TestValidateCompactSettings(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		keep      int
+		config    Config
+		wantErr   bool
+	}{
+		{"defaults", defaultCompactThreshold, recentMessagesToKeep, Config{}, false},
+		{"keep only the latest message", 0, 1, Config{}, false},
+		{"keep nothing", defaultCompactThreshold, 0, Config{}, true},
+		{"negative keep", defaultCompactThreshold, -1, Config{}, true},
+		{"negative config keep", defaultCompactThreshold, recentMessagesToKeep, Config{CompactKeep: -2}, true},
+		{"config keep", defaultCompactThreshold, recentMessagesToKeep, Config{CompactKeep: 1}, false},
+		{"negative threshold", -1, recentMessagesToKeep, Config{}, true},
+		{"negative config threshold", defaultCompactThreshold, recentMessagesToKeep, Config{CompactThreshold: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCompactSettings(tt.threshold, tt.keep, &tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateCompactSettings(%d, %d, %+v) error = %v, wantErr %v", tt.threshold, tt.keep, tt.config, err, tt.wantErr)
+			}
+		})
+	}
+}
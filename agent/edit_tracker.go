@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+)
+
+// Default number of edits to a single file after which tools start warning about thrashing
+const defaultEditSoftCap = 15
+
+// Number of edits to a single file after which tools warn (0 disables the warning)
+var editSoftCap = defaultEditSoftCap
+
+// Per-file edit counts for the current session
+var (
+	editCounts   = make(map[string]int)
+	editCountsMu sync.Mutex
+)
+
+// recordEdit counts an edit to a file and returns a warning once the soft cap is exceeded
+func recordEdit(path string) string {
+	editCountsMu.Lock()
+	defer editCountsMu.Unlock()
+
+	path = filepath.Clean(path)
+	editCounts[path]++
+	count := editCounts[path]
+
+	if editSoftCap <= 0 || count <= editSoftCap {
+		return ""
+	}
+	return fmt.Sprintf("\n\nWarning: %s has now been edited %d times this session. You may be going in circles; "+
+		"step back, re-view the whole file with view_file, and plan the remaining changes before editing again.", path, count)
+}
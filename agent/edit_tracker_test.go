@@ -0,0 +1,29 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecordEditWarnsAfterSoftCap(t *testing.T) {
+	editSoftCap = 3
+	editCounts = make(map[string]int)
+	t.Cleanup(func() {
+		editSoftCap = defaultEditSoftCap
+		editCounts = make(map[string]int)
+	})
+
+	for edit := 1; edit <= 3; edit++ {
+		if warning := recordEdit("src/main.go"); warning != "" {
+			t.Fatalf("edit %d: recordEdit() = %q, want no warning before the soft cap is exceeded", edit, warning)
+		}
+	}
+	// Equivalent paths count towards the same file
+	warning := recordEdit("./src/main.go")
+	if !strings.Contains(warning, "src/main.go has now been edited 4 times") {
+		t.Errorf("edit 4: recordEdit() = %q, want the thrashing warning", warning)
+	}
+	if warning := recordEdit("src/other.go"); warning != "" {
+		t.Errorf("recordEdit(src/other.go) = %q, want other files counted separately", warning)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// Process exit codes reported by the CLI
+const (
+	ExitSuccess     = 0 // All conflicts resolved
+	ExitRuntimeErr  = 1 // The agent failed while running
+	ExitUnresolved  = 2 // The agent finished but conflicts remain
+	ExitConfigError = 3 // Bad configuration or rejected credentials
+)
+
+// exitCodeFor maps the outcome of a run to the process exit code
+func exitCodeFor(runErr error, unresolvedFiles []string) int {
+	if runErr != nil {
+		var apiErr *anthropic.Error
+		if errors.As(runErr, &apiErr) &&
+			(apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+			return ExitConfigError
+		}
+		return ExitRuntimeErr
+	}
+
+	if len(unresolvedFiles) > 0 {
+		return ExitUnresolved
+	}
+
+	return ExitSuccess
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+func TestExitCodeFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		runErr     error
+		unresolved []string
+		want       int
+	}{
+		{"all resolved", nil, nil, ExitSuccess},
+		{"conflicts remain", nil, []string{"a.go"}, ExitUnresolved},
+		{"runtime error", errors.New("stream failed"), nil, ExitRuntimeErr},
+		{"runtime error with conflicts left", errors.New("stream failed"), []string{"a.go"}, ExitRuntimeErr},
+		{"unresolved check failed", fmt.Errorf("failed to check for unresolved conflicts: %w", errors.New("not a git repository")), nil, ExitRuntimeErr},
+		{"server error", &anthropic.Error{StatusCode: http.StatusInternalServerError}, nil, ExitRuntimeErr},
+		{"invalid key", &anthropic.Error{StatusCode: http.StatusUnauthorized}, nil, ExitConfigError},
+		{"wrapped forbidden", fmt.Errorf("request failed: %w", &anthropic.Error{StatusCode: http.StatusForbidden}), nil, ExitConfigError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCodeFor(tt.runErr, tt.unresolved); got != tt.want {
+				t.Errorf("exitCodeFor() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// gitIgnoredPaths asks git which of the given paths it ignores. Git applies every .gitignore
+// in the tree, .git/info/exclude and the global excludes file, with anchoring, ** and ! negation,
+// so the answer matches what git status shows. Tracked files are never reported as ignored.
+// Returns an error outside a git repository.
+func gitIgnoredPaths(ctx context.Context, paths []string) (map[string]bool, error) {
+	ignored := make(map[string]bool)
+	if len(paths) == 0 {
+		return ignored, nil
+	}
+
+	var input bytes.Buffer
+	for _, path := range paths {
+		input.WriteString(filepath.ToSlash(path))
+		input.WriteByte(0)
+	}
+
+	cmd := toolCommand(ctx, "git", "check-ignore", "--stdin", "-z")
+	cmd.Stdin = &input
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// check-ignore exits with 1 when none of the paths are ignored
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			return nil, fmt.Errorf("git check-ignore failed: %s\nStderr: %s", err, stderr.String())
+		}
+	}
+
+	for _, path := range strings.Split(stdout.String(), "\x00") {
+		if path != "" {
+			ignored[filepath.FromSlash(path)] = true
+		}
+	}
+	return ignored, nil
+}
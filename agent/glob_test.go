@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.ts", "src/app/main.ts", true},
+		{"*.ts", "main.go", false},
+		{"src/**/*.ts", "src/main.ts", true},
+		{"src/**/*.ts", "src/app/components/button.ts", true},
+		{"src/**/*.ts", "lib/app/main.ts", false},
+		{"src/**/*.ts", "src/app/main.go", false},
+		{"**/test/*.go", "pkg/a/test/x.go", true},
+		{"**/test/*.go", "test/x.go", true},
+		{"**/test/*.go", "pkg/test/sub/x.go", false},
+		{"src/**", "src/a/b/c.txt", true},
+		{"./src/*.ts", "src/main.ts", true},
+		{"src/*.ts", "src/app/main.ts", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFindMatchingFilesNestedGlobs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeTree(t, map[string]string{
+		"src/main.ts":                "",
+		"src/app/components/view.ts": "",
+		"src/app/view.test.ts":       "",
+		"src/app/style.css":          "",
+		"lib/util.ts":                "",
+		"vendor/src/dep.ts":          "",
+	})
+
+	tests := []struct {
+		include string
+		exclude string
+		want    []string
+	}{
+		{"src/**/*.ts", "", []string{"src/app/components/view.ts", "src/app/view.test.ts", "src/main.ts"}},
+		{"src/**/*.ts", "*.test.ts", []string{"src/app/components/view.ts", "src/main.ts"}},
+		{"src/**/*.ts, *.css", "src/app/components/**", []string{"src/app/style.css", "src/app/view.test.ts", "src/main.ts"}},
+		{"*.ts", "vendor/**, lib/**", []string{"src/app/components/view.ts", "src/app/view.test.ts", "src/main.ts"}},
+	}
+	for _, tt := range tests {
+		got, err := findMatchingFiles(context.Background(), tt.include, tt.exclude)
+		if err != nil {
+			t.Fatalf("findMatchingFiles(%q, %q) error = %v", tt.include, tt.exclude, err)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("findMatchingFiles(%q, %q) = %v, want %v", tt.include, tt.exclude, got, tt.want)
+		}
+	}
+
+	if _, err := findMatchingFiles(context.Background(), "src/[*.ts", ""); err == nil {
+		t.Error("findMatchingFiles() accepted a malformed pattern, want an error")
+	}
+}
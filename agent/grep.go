@@ -2,9 +2,8 @@ package main
 
 import (
 	"bufio"
-	"bytes"
+	"context"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -20,20 +19,15 @@ const memoryMapThreshold = 10 * 1024 * 1024 // 10MB
 // Maximum number of files to process in parallel
 var maxParallelFiles = runtime.GOMAXPROCS(0) * 2
 
-// Common binary file signatures
-var binaryFileSignatures = [][]byte{
-	{0x7F, 0x45, 0x4C, 0x46}, // ELF
-	{0x4D, 0x5A},             // PE/DOS
-	{0xFE, 0xED, 0xFA, 0xCE}, // Mach-O
-	{0x50, 0x4B, 0x03, 0x04}, // ZIP
-	{0x1F, 0x8B},             // gzip
-}
-
 // GrepMatch represents a single match result from a grep operation
 type GrepMatch struct {
 	Path    string // File path where the match was found
 	Line    int    // Line number of the match
 	Content string // The matching line content
+
+	// Surrounding lines, when context was requested
+	Before []string // Lines immediately before the match, in order
+	After  []string // Lines immediately after the match, in order
 }
 
 // grepResult is used to collect results from parallel workers
@@ -44,22 +38,27 @@ type grepResult struct {
 
 // grep performs a regex search across files in the project
 // pattern: regex pattern to search for
-// includePattern: glob pattern to filter which files to search in
+// includePattern: comma-separated glob patterns selecting which files to search in (see matchGlob)
 // caseSensitive: whether the search should be case-sensitive
-func grep(pattern string, includePattern string, caseSensitive bool) ([]GrepMatch, error) {
+func grep(ctx context.Context, pattern string, includePattern string, caseSensitive bool) ([]GrepMatch, error) {
+	return grepWithContext(ctx, pattern, includePattern, "", caseSensitive, 0, 0)
+}
+
+// grepWithContext is grep that also skips files matching the comma-separated excludePattern
+// and collects up to contextBefore and contextAfter lines around each match
+func grepWithContext(ctx context.Context, pattern string, includePattern string, excludePattern string, caseSensitive bool, contextBefore, contextAfter int) ([]GrepMatch, error) {
 	// Pre-compile the regex pattern
-	flags := regexp.Compile
 	if !caseSensitive {
-		flags = regexp.CompilePOSIX
+		pattern = "(?i)" + pattern
 	}
-	
-	re, err := flags(pattern)
+
+	re, err := regexp.Compile(pattern)
 	if err != nil {
 		return nil, fmt.Errorf("invalid regex pattern: %w", err)
 	}
 
 	// Find all files matching the include pattern
-	matchingFiles, err := findMatchingFiles(includePattern)
+	matchingFiles, err := findMatchingFiles(ctx, includePattern, excludePattern)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find matching files: %w", err)
 	}
@@ -67,28 +66,32 @@ func grep(pattern string, includePattern string, caseSensitive bool) ([]GrepMatc
 	// Initialize result channel and wait group
 	results := make(chan grepResult, len(matchingFiles))
 	var wg sync.WaitGroup
-	
+
 	// Create a buffered channel to limit parallel processing
 	semaphore := make(chan struct{}, maxParallelFiles)
-	
+
 	// Initialize an atomic counter for progress tracking
 	var filesProcessed uint64
 	totalFiles := uint64(len(matchingFiles))
 
-	// Process files in parallel
+	// Process files in parallel, not even opening files whose extension says they are binary
 	for _, filePath := range matchingFiles {
+		if hasBinaryExtension(filePath) {
+			atomic.AddUint64(&filesProcessed, 1)
+			continue
+		}
 		wg.Add(1)
 		go func(path string) {
 			defer wg.Done()
-			
+
 			// Acquire semaphore
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
-			
+
 			// Search the file
-			matches, err := searchFile(path, re, caseSensitive)
+			matches, err := searchFile(path, re, contextBefore, contextAfter)
 			results <- grepResult{matches: matches, err: err}
-			
+
 			// Update progress
 			processed := atomic.AddUint64(&filesProcessed, 1)
 			if processed%100 == 0 || processed == totalFiles {
@@ -118,8 +121,8 @@ func grep(pattern string, includePattern string, caseSensitive bool) ([]GrepMatc
 	return allMatches, nil
 }
 
-// searchFile searches a single file for matches
-func searchFile(filePath string, re *regexp.Regexp, caseSensitive bool) ([]GrepMatch, error) {
+// searchFile searches a single file for matches, keeping the requested number of context lines
+func searchFile(filePath string, re *regexp.Regexp, contextBefore, contextAfter int) ([]GrepMatch, error) {
 	// Open the file
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -164,23 +167,40 @@ func searchFile(filePath string, re *regexp.Regexp, caseSensitive bool) ([]GrepM
 	scanner := bufio.NewScanner(file)
 	scanner.Buffer(*buf, 1024*1024) // 1MB max line length
 
+	var previous []string // The last contextBefore lines
+	var pending []int     // Indexes of matches still collecting lines after them
+
 	for scanner.Scan() {
 		lineNum++
 		line := scanner.Text()
 
-		var searchLine string
-		if !caseSensitive {
-			searchLine = strings.ToLower(line)
-		} else {
-			searchLine = line
+		// Hand this line to matches still waiting for trailing context
+		stillPending := pending[:0]
+		for _, i := range pending {
+			matches[i].After = append(matches[i].After, line)
+			if len(matches[i].After) < contextAfter {
+				stillPending = append(stillPending, i)
+			}
 		}
+		pending = stillPending
 
-		if re.MatchString(searchLine) {
+		if re.MatchString(line) {
 			matches = append(matches, GrepMatch{
 				Path:    filePath,
 				Line:    lineNum,
 				Content: line,
+				Before:  append([]string(nil), previous...),
 			})
+			if contextAfter > 0 {
+				pending = append(pending, len(matches)-1)
+			}
+		}
+
+		if contextBefore > 0 {
+			previous = append(previous, line)
+			if len(previous) > contextBefore {
+				previous = previous[1:]
+			}
 		}
 	}
 
@@ -191,47 +211,14 @@ func searchFile(filePath string, re *regexp.Regexp, caseSensitive bool) ([]GrepM
 	return matches, nil
 }
 
-// isBinaryFile checks if a file is likely binary by looking at its first few bytes
-func isBinaryFile(file *os.File) bool {
-	// Read first 512 bytes
-	buf := make([]byte, 512)
-	n, err := file.Read(buf)
-	if err != nil && err != io.EOF {
-		return true // Assume binary on error
-	}
-	buf = buf[:n]
-
-	// Check for common binary signatures
-	for _, sig := range binaryFileSignatures {
-		if bytes.HasPrefix(buf, sig) {
-			return true
-		}
-	}
-
-	// Count zero bytes
-	zeros := 0
-	for _, b := range buf {
-		if b == 0 {
-			zeros++
-		}
-	}
-
-	// If more than 10% of the bytes are zero, likely binary
-	return zeros > len(buf)/10
-}
-
-// findMatchingFiles returns a list of files that match the given glob pattern
-func findMatchingFiles(pattern string) ([]string, error) {
-	// Read .gitignore if it exists
-	ignorePatterns := make(map[string]bool)
-	if ignoreFile, err := os.Open(".gitignore"); err == nil {
-		defer ignoreFile.Close()
-		scanner := bufio.NewScanner(ignoreFile)
-		for scanner.Scan() {
-			pattern := strings.TrimSpace(scanner.Text())
-			if pattern != "" && !strings.HasPrefix(pattern, "#") {
-				ignorePatterns[pattern] = true
-			}
+// findMatchingFiles returns a list of files that match one of the comma-separated include
+// patterns and none of the comma-separated exclude patterns
+func findMatchingFiles(ctx context.Context, includePattern string, excludePattern string) ([]string, error) {
+	includes := splitPatterns(includePattern)
+	excludes := splitPatterns(excludePattern)
+	for _, pattern := range append(append([]string(nil), includes...), excludes...) {
+		if err := validateGlob(pattern); err != nil {
+			return nil, err
 		}
 	}
 
@@ -242,36 +229,38 @@ func findMatchingFiles(pattern string) ([]string, error) {
 	var wg sync.WaitGroup
 	errChan := make(chan error, 1) // Buffer of 1 to prevent goroutine leak
 
-	// Walk the directory tree
+	// Walk the directory tree, stopping early if the tool call times out
 	err := filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
-		// Skip directories and hidden files
+		// Skip directories and hidden files (but not the root itself, which is named ".")
 		if info.IsDir() {
+			if path == "." {
+				return nil
+			}
 			if strings.HasPrefix(info.Name(), ".") || info.Name() == "node_modules" {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		// Skip hidden files and files matching .gitignore patterns
+		// Skip hidden files
 		if strings.HasPrefix(info.Name(), ".") {
 			return nil
 		}
 
-		// Check .gitignore patterns
-		for ignorePattern := range ignorePatterns {
-			if matched, _ := filepath.Match(ignorePattern, info.Name()); matched {
-				return nil
-			}
+		// Skip files the repository config ignores
+		if matchesAnyPattern(repoIgnorePatterns, path) {
+			return nil
 		}
 
-		// Check if file matches the pattern
-		if match, err := filepath.Match(pattern, info.Name()); err != nil {
-			return err
-		} else if match {
+		// Check if file matches the patterns
+		if matchesAnyPattern(includes, path) && !matchesAnyPattern(excludes, path) {
 			mu.Lock()
 			matches = append(matches, path)
 			mu.Unlock()
@@ -290,6 +279,29 @@ func findMatchingFiles(pattern string) ([]string, error) {
 	case err := <-errChan:
 		return nil, err
 	default:
-		return matches, nil
+		return filterIgnored(ctx, matches), nil
+	}
+}
+
+// filterIgnored drops the paths git ignores. Outside a git repository it falls back to
+// the patterns in the top-level .gitignore.
+func filterIgnored(ctx context.Context, paths []string) []string {
+	ignored, err := gitIgnoredPaths(ctx, paths)
+	if err != nil {
+		patterns := loadGitignorePatterns()
+		ignored = make(map[string]bool)
+		for _, path := range paths {
+			if shouldIgnore(path, false, patterns) {
+				ignored[path] = true
+			}
+		}
+	}
+
+	var kept []string
+	for _, path := range paths {
+		if !ignored[path] {
+			kept = append(kept, path)
+		}
 	}
-}
\ No newline at end of file
+	return kept
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// writeTree creates the given files under the working directory
+func writeTree(t *testing.T, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGrepCaseSensitivity(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeTree(t, map[string]string{
+		"lower.txt": "func handler() {}\n",
+		"upper.txt": "const HANDLER = 1\n",
+	})
+
+	tests := []struct {
+		name          string
+		pattern       string
+		caseSensitive bool
+		want          []string
+	}{
+		{"uppercase pattern, insensitive", "HANDLER", false, []string{"lower.txt", "upper.txt"}},
+		{"lowercase pattern, insensitive", "handler", false, []string{"lower.txt", "upper.txt"}},
+		{"mixed case regex, insensitive", "Hand[a-z]+R", false, []string{"lower.txt", "upper.txt"}},
+		{"uppercase pattern, sensitive", "HANDLER", true, []string{"upper.txt"}},
+		{"lowercase pattern, sensitive", "handler", true, []string{"lower.txt"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := grep(context.Background(), tt.pattern, "*.txt", tt.caseSensitive)
+			if err != nil {
+				t.Fatalf("grep() error = %v", err)
+			}
+			var got []string
+			for _, match := range matches {
+				got = append(got, match.Path)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("grep() matched %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
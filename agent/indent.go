@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+)
+
+// Indent width assumed for space-indented code when it cannot be inferred
+const defaultIndentWidth = 4
+
+// indentStyle describes how a file indents its lines
+type indentStyle struct {
+	useTabs bool
+	width   int // Spaces per indent level; unused for tabs
+}
+
+// detectIndentStyle infers the dominant indent style of some lines.
+// Returns false if no line is indented.
+func detectIndentStyle(lines []string) (indentStyle, bool) {
+	tabLines, spaceLines := 0, 0
+	widths := make(map[int]int)
+	previous := 0
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		switch line[0] {
+		case '\t':
+			tabLines++
+		case ' ':
+			spaceLines++
+		}
+
+		// Tab-indented lines say nothing about the width of space indents, so they must not
+		// reset the indentation the next space-indented line is measured against
+		if line[0] == '\t' {
+			continue
+		}
+
+		// Space indent width is the most common increase in indentation between lines
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if indent > previous && line[0] == ' ' {
+			widths[indent-previous]++
+		}
+		previous = indent
+	}
+
+	if tabLines == 0 && spaceLines == 0 {
+		return indentStyle{}, false
+	}
+	if tabLines > spaceLines {
+		return indentStyle{useTabs: true}, true
+	}
+
+	width, best := defaultIndentWidth, 0
+	for w, count := range widths {
+		if w >= 2 && w <= 8 && (count > best || (count == best && w < width)) {
+			width, best = w, count
+		}
+	}
+	return indentStyle{width: width}, true
+}
+
+// reindent rewrites the leading indentation of content from its own style to target.
+// Lines whose indentation mixes tabs and spaces are left untouched, as is content
+// whose style cannot be detected or already matches.
+func reindent(content string, target indentStyle) string {
+	lines := strings.Split(content, "\n")
+	source, ok := detectIndentStyle(lines)
+	if !ok || source == target || (source.useTabs && target.useTabs) {
+		return content
+	}
+
+	for i, line := range lines {
+		body := strings.TrimLeft(line, " \t")
+		leading := line[:len(line)-len(body)]
+		if leading == "" || body == "" {
+			continue
+		}
+
+		var levels, remainder int
+		switch {
+		case source.useTabs && strings.Trim(leading, "\t") == "":
+			levels = len(leading)
+		case !source.useTabs && strings.Trim(leading, " ") == "":
+			levels, remainder = len(leading)/source.width, len(leading)%source.width
+		default:
+			// Mixed or foreign indentation: keep it as written
+			continue
+		}
+
+		unit := "\t"
+		if !target.useTabs {
+			unit = strings.Repeat(" ", target.width)
+		}
+		lines[i] = strings.Repeat(unit, levels) + strings.Repeat(" ", remainder) + body
+	}
+	return strings.Join(lines, "\n")
+}
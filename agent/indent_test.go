@@ -0,0 +1,75 @@
+package main
+
+import "testing"
+
+func TestDetectIndentStyle(t *testing.T) {
+	tests := []struct {
+		name   string
+		lines  []string
+		want   indentStyle
+		wantOK bool
+	}{
+		{"two spaces", []string{"a:", "  b:", "    c", "  d"}, indentStyle{width: 2}, true},
+		{"four spaces", []string{"def f():", "    if x:", "        y", "    z"}, indentStyle{width: 4}, true},
+		{"tabs", []string{"func f() {", "\tif x {", "\t\ty", "\t}", "}"}, indentStyle{useTabs: true}, true},
+		{
+			// Tab lines between space lines must not make the next space indent look like a new level
+			"spaces with stray tab lines",
+			[]string{"a:", "    b:", "        c", "\tstray", "        d", "\tstray", "        e", "\tstray", "        f"},
+			indentStyle{width: 4},
+			true,
+		},
+		{"unindented", []string{"a", "b"}, indentStyle{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := detectIndentStyle(tt.lines)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("detectIndentStyle() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestReindent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		target  indentStyle
+		want    string
+	}{
+		{
+			name:    "spaces to tabs",
+			content: "if x {\n    y()\n        z()\n}",
+			target:  indentStyle{useTabs: true},
+			want:    "if x {\n\ty()\n\t\tz()\n}",
+		},
+		{
+			name:    "tabs to spaces",
+			content: "def f():\n\tif x:\n\t\ty()",
+			target:  indentStyle{width: 2},
+			want:    "def f():\n  if x:\n    y()",
+		},
+		{
+			name:    "mixed indentation is kept as written",
+			content: "if x {\n    y()\n    z()\n\t  w()\n}",
+			target:  indentStyle{useTabs: true},
+			want:    "if x {\n\ty()\n\tz()\n\t  w()\n}",
+		},
+		{
+			name:    "matching style is unchanged",
+			content: "if x {\n\ty()\n}",
+			target:  indentStyle{useTabs: true},
+			want:    "if x {\n\ty()\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reindent(tt.content, tt.target); got != tt.want {
+				t.Errorf("reindent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
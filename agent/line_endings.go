@@ -0,0 +1,23 @@
+package main
+
+import "strings"
+
+// toLF converts content whose dominant line ending is CRLF to LF, so edits can split and join
+// on "\n" alone. It returns the converted content and the line ending to restore with fromLF.
+// Content that mostly uses LF is returned unchanged, with "\n" as its line ending.
+func toLF(content string) (string, string) {
+	crlf := strings.Count(content, "\r\n")
+	if crlf == 0 || crlf*2 < strings.Count(content, "\n") {
+		return content, "\n"
+	}
+	return strings.ReplaceAll(content, "\r\n", "\n"), "\r\n"
+}
+
+// fromLF converts LF content back to the given line ending. Whether the content ends with a
+// newline is preserved as is.
+func fromLF(content, eol string) string {
+	if eol == "\n" {
+		return content
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(content, "\r\n", "\n"), "\n", eol)
+}
@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+func TestLineEndingsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantEOL string
+		want    string // Content after converting to LF and back
+	}{
+		{"LF", "a\nb\n", "\n", "a\nb\n"},
+		{"CRLF", "a\r\nb\r\n", "\r\n", "a\r\nb\r\n"},
+		{"mostly CRLF", "a\r\nb\r\nc\n", "\r\n", "a\r\nb\r\nc\r\n"},
+		{"mostly LF", "a\nb\nc\r\n", "\n", "a\nb\nc\r\n"},
+		{"no trailing newline", "a\r\nb", "\r\n", "a\r\nb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, eol := toLF(tt.content)
+			if eol != tt.wantEOL {
+				t.Errorf("toLF() line ending = %q, want %q", eol, tt.wantEOL)
+			}
+			if got := fromLF(text, eol); got != tt.want {
+				t.Errorf("fromLF(toLF()) = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
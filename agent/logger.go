@@ -1,19 +1,94 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 )
 
+// Tools that modify the working tree or repository
+var mutatingTools = map[string]bool{
+	"abort_merge":              true,
+	"accept_version":           true,
+	"append_to_file":           true,
+	"create_file":              true,
+	"delete_file":              true,
+	"edit_chunk_part":          true,
+	"edit_file_chunk":          true,
+	"edit_file_line":           true,
+	"find_replace_all":         true,
+	"move_file":                true,
+	"prepend_to_file":          true,
+	"git_save_changes":         true,
+	"regenerate_file":          true,
+	"resolve_chunk":            true,
+	"resolve_generated_file":   true,
+	"resolve_identical_chunks": true,
+	"resolve_union_files":      true,
+	"restore_all":              true,
+	"restore_file":             true,
+	"run_command":              true,
+}
+
+// isMutatingTool checks if a tool modifies the working tree or repository
+func isMutatingTool(name string) bool {
+	return mutatingTools[name]
+}
+
+// toolInfoLine returns the permanent line that info tools mode prints for a tool call, and
+// whether one should be printed at all: only mutating tools get one
+func toolInfoLine(infoTools bool, name string, input interface{}) (string, bool) {
+	if !infoTools || !isMutatingTool(name) {
+		return "", false
+	}
+	return describeMutatingToolCall(name, input), true
+}
+
+// describeMutatingToolCall builds a concise one-line description of a mutating tool call
+func describeMutatingToolCall(name string, input interface{}) string {
+	var raw []byte
+	switch v := input.(type) {
+	case string:
+		raw = []byte(v)
+	case json.RawMessage:
+		raw = v
+	}
+
+	var params struct {
+		Path    string `json:"path"`
+		Message string `json:"message"`
+		Find    string `json:"find"`
+		Source  string `json:"source"`
+		Command string `json:"command"`
+	}
+	if err := json.Unmarshal(raw, &params); err == nil {
+		switch {
+		case params.Path != "":
+			return fmt.Sprintf("%s: %s", name, params.Path)
+		case params.Source != "":
+			return fmt.Sprintf("%s: %s", name, params.Source)
+		case params.Message != "":
+			return fmt.Sprintf("%s: %q", name, params.Message)
+		case params.Find != "":
+			return fmt.Sprintf("%s: %q", name, params.Find)
+		case params.Command != "":
+			return fmt.Sprintf("%s: %s", name, params.Command)
+		}
+	}
+	return name
+}
+
 // Logger manages logging output based on debug flag
 type Logger struct {
 	debugMode   bool
+	infoTools   bool   // Print a permanent line for each mutating tool call, even outside debug mode
 	currentLine string // Track the current ephemeral line for replacements
 }
 
-// NewLogger creates a new Logger with the provided debug state
-func NewLogger(debugMode bool) *Logger {
+// NewLogger creates a new Logger with the provided debug and tool info states
+func NewLogger(debugMode bool, infoTools bool) *Logger {
 	return &Logger{
 		debugMode:   debugMode,
+		infoTools:   infoTools,
 		currentLine: "",
 	}
 }
@@ -56,10 +131,12 @@ func (l *Logger) replaceLine(text string) {
 	l.currentLine = text
 }
 
-// ToolCall logs a tool call in debug mode
+// ToolCall logs a tool call in debug mode, or a concise permanent line for mutating tools in info tools mode
 func (l *Logger) ToolCall(name string, input interface{}) {
 	if l.debugMode {
 		l.replaceLine(fmt.Sprintf("\u001b[92mTool\u001b[0m: %s(%v)", name, input))
+	} else if line, ok := toolInfoLine(l.infoTools, name, input); ok {
+		l.Info("%s\n", line)
 	}
 }
 
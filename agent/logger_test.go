@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+func TestToolInfoLine(t *testing.T) {
+	tests := []struct {
+		name      string
+		infoTools bool
+		tool      string
+		input     string
+		want      string
+		wantOK    bool
+	}{
+		{"mutating tool", true, "edit_file_line", `{"path":"main.go","line":3}`, "edit_file_line: main.go", true},
+		{"commit message", true, "git_save_changes", `{"message":"resolve conflicts"}`, `git_save_changes: "resolve conflicts"`, true},
+		{"read-only tool", true, "view_file", `{"path":"main.go"}`, "", false},
+		{"info tools off", false, "edit_file_line", `{"path":"main.go"}`, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := toolInfoLine(tt.infoTools, tt.tool, tt.input)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("toolInfoLine(%v, %q) = %q, %v, want %q, %v", tt.infoTools, tt.tool, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe to write from the logger's background goroutines
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestGsLoggerInfoToolsLogsMutatingCallsOnly(t *testing.T) {
+	var output syncBuffer
+	previous := color.Output
+	color.Output = &output
+	t.Cleanup(func() { color.Output = previous })
+
+	logger := NewGsLogger(false, true, nil, "stub")
+	t.Cleanup(logger.spinner.Stop)
+	logger.ToolCall("view_file", `{"path":"main.go"}`)
+	logger.ToolCall("edit_file_line", `{"path":"main.go","line":3}`)
+	// Let the ephemeral tool call lines be shown too, so they are not mistaken for info lines
+	time.Sleep(50 * time.Millisecond)
+
+	got := output.String()
+	if strings.Count(got, "✏️") != 1 || !strings.Contains(got, "✏️ edit_file_line: main.go") {
+		t.Errorf("output = %q, want a single info line for edit_file_line", got)
+	}
+}
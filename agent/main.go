@@ -4,15 +4,13 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
-	"errors"
 	"flag"
 	"fmt"
-	"math/rand"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/anthropics/anthropic-sdk-go"
-	"github.com/anthropics/anthropic-sdk-go/option"
 	"github.com/invopop/jsonschema"
 )
 
@@ -21,13 +19,28 @@ type Agent struct {
 	getUserMessage func() (string, bool)
 	tools          []ToolDefinition
 	logger         *GsLogger
+	model          anthropic.Model
+	maxTokens      int64
+	retryBudget    time.Duration // Total time to spend retrying a failed request before giving up
+	toolTimeout    time.Duration // Time a single tool call may run (0 disables the limit)
+
+	// Token usage accumulated over the run
+	inputTokens  int64
+	outputTokens int64
+
+	// Conversation compaction settings
+	compactThreshold int // Estimated token count past which older tool results are compacted
+	compactKeep      int // Number of most recent messages that are never compacted
 }
 
+// Text the agent outputs once every conflict is resolved and committed
+const allDoneSentinel = "[ALL DONE]"
+
 type ToolDefinition struct {
 	Name        string                         `json:"name"`
 	Description string                         `json:"description"`
 	InputSchema anthropic.ToolInputSchemaParam `json:"input_schema"`
-	Function    func(input json.RawMessage) (string, error)
+	Function    func(ctx context.Context, input json.RawMessage) (string, error)
 }
 
 var DefaultPrompt = `
@@ -49,12 +62,19 @@ Your mission: Resolve all Git merge conflicts across files such that:
     - **Review recent commits and changes**.
     - **Analyze the codebase for common patterns and conventions**.
     Example tool calls:
+    - Get an overview of the layout, languages, README, and recent commits in one call: repo_summary({})
     - See recent commits: see_git_history({})
+    - See the overall change being merged in: see_branch_diff({ "from": "HEAD", "to": "MERGE_HEAD" })
     - List files: list_files({})
+    - List a directory tree two levels deep: list_files({ "recursive": true, "max_depth": 2 })
     - Read file contents: view_file({ "path": "README.md" })
 
 1. **Identify Files with Merge Conflicts**
 	Example tool call: see_git_status({})
+	- For chunk and line counts per file, to plan the order of work: conflict_summary({})
+	- Then, always clear out trivial chunks whose two sides are identical before anything else: resolve_identical_chunks({})
+	- Then, resolve conflicts in line-oriented files like .gitignore by keeping both sides' lines: resolve_union_files({})
+	- If one side should win an entire file (e.g. the repository config names a strategy for it), take that version wholesale: accept_version({ "path": "package-lock.json", "side": "theirs" })
 
 2. **For Each Conflicted File**:
     Make sure you completely understand the contents of the file and the changes that are being made.
@@ -74,6 +94,7 @@ Your mission: Resolve all Git merge conflicts across files such that:
 	      "commit_id": "a1b2c3"
 	    })
     - Finally, view the git conflict chunks within the file: see_file_chunks({ "path": "src/utils.js" })
+    - For logic-heavy conflicts, see what each side changed relative to the merge base: see_conflict_diff({ "path": "src/utils.js" })
 
 3. **Making Edits**:
    - Once you've identified how you want to change the file, make edits to replace the contents of each conflicting chunk, one at a time.
@@ -87,6 +108,13 @@ Your mission: Resolve all Git merge conflicts across files such that:
 	      "chunk_id": 0,
 	      "new_content": "function processData(data) {\n  // Merged solution\n  return data.filter(item => item.isValid);\n}"
 	    })
+   - When a chunk should simply keep one side or both, don't retype it: resolve_chunk({ "path": "src/utils.js", "chunk_id": 1, "strategy": "theirs" })
+      - Strategies are "ours", "theirs", "both" (ours then theirs), and "union" (both, without repeated lines).
+   - To resolve several chunks of one file in a single call, pass edits; they are applied from the bottom up for you, so use the chunk IDs exactly as see_file_chunks showed them:
+   		edit_file_chunk({
+	      "path": "src/utils.js",
+	      "edits": [{ "chunk_id": 0, "new_content": "..." }, { "chunk_id": 2, "new_content": "..." }]
+	    })
 
 3.5 **Bonus step**:
    - Sometimes, it may be the case that the definition for a symbol has changed such that all usages of that symbol (ie its name) should also be changed. In those cases, don't be afraid to find it across the whole project:
@@ -108,6 +136,10 @@ Your mission: Resolve all Git merge conflicts across files such that:
 	       "end_line": 15,
 	       "new_content": "This content will replace\nall lines from 10 to 15\nwith these three lines"
 	     })
+	- To add lines between existing ones without replacing any, insert them before a line:
+		edit_file_line({ "path": "path/to/file.txt", "start_line": 10, "new_content": "inserted line", "insert": true })
+	- To add lines at the very end or start of a file without counting its lines, use append_to_file or prepend_to_file:
+		append_to_file({ "path": "path/to/file.txt", "content": "new last line" })
 	- If Find and Replace All is more appropriate (ie for when the name of the symbol itself has changed, or a common import path has changed):
 		find_replace_all({
 			"find": "someFunction",
@@ -122,6 +154,7 @@ Your mission: Resolve all Git merge conflicts across files such that:
    Example tool calls:
    - Double-check which files should have been modified and resolved: see_git_status({})
    - For each of those files, ensure the final output is correct, syntax-error-free, with no duplicate lines or weird artifacts of our editing process, and looks functional. Include line numbers for precise edits later: view_file({ "path": "src/utils.js", "with_line_numbers": true })
+   - After resolving each file, check that it still parses: check_syntax({ "path": "src/utils.js" })
    		- If there are small precise edits you wish to make to individual lines at this point:
 		    edit_file_line({
 		       "path": "path/to/file.txt",
@@ -131,10 +164,18 @@ Your mission: Resolve all Git merge conflicts across files such that:
 		     })
 		- If there are larger edits or structural changes needed, consider going back to an earlier step above and trying again.
 		- After making each precise edit, RE-VERIFY THE FINAL OUTPUT, AGAIN.
+   - If the repository has a test command or allowed commands (see Repository Configuration), check that the resolution builds and passes before saving. Fix anything your edits broke:
+   		run_command({})
+   		run_command({ "command": "go build ./..." })
    - Save changes once you're completely satisfied with the results.
    		git_save_changes({
 	      "message": "Resolve conflicts in utils.js"
 	    })
+   - To keep git's prepared merge message (see get_merge_message({})), commit with it instead:
+   		git_save_changes({
+	      "message": "Resolve conflicts in utils.js",
+	      "use_merge_message": true
+	    })
 
 5. **Final Confirmation**:
    - When you are sure all conflicts are resolved and committed locally, make sure to output:
@@ -156,6 +197,8 @@ Your mission: Resolve all Git merge conflicts across files such that:
 ⚠️ **Robustness Hints**
 
 - Assume tools can fail; retry with smaller, more accurate edits when they do.
+- If an edit to a file goes wrong, undo it with restore_file({ "path": "src/utils.js" }) rather than patching over it.
+- abort_merge({}) throws away the whole merge. Use it only as a last resort, when the working tree cannot be repaired any other way.
 - Use descriptive commit messages, but avoid verbosity.
 - If faced with ambiguous conflicts, favor safe integration of both versions.
 
@@ -170,14 +213,40 @@ func main() {
 	// --- Parse command line arguments ---
 	debugMode := flag.Bool("d", false, "Enable debug mode with verbose logging")
 	flag.BoolVar(debugMode, "debug", false, "Enable debug mode with verbose logging")
+	infoTools := flag.Bool("info-tools", false, "Print a line for every tool call that modifies files, even outside debug mode")
 	apiKeyFlag := flag.String("api-key", "", "Anthropic API key. If provided, will be saved for future use")
+	baseURLFlag := flag.String("base-url", "", "Custom Anthropic API base URL, e.g. for a proxy or gateway. If provided, will be saved for future use")
+	authorNameFlag := flag.String("author-name", "", "Name to author commits as (the committer stays the configured git user). If provided, will be saved for future use")
+	authorEmailFlag := flag.String("author-email", "", "Email to author commits as. If provided, will be saved for future use")
+	modelFlag := flag.String("model", "", "Model to resolve conflicts with, overriding the configured model for this run (default "+string(defaultModel)+")")
+	maxTokensFlag := flag.Int64("max-tokens", 0, fmt.Sprintf("Maximum tokens per agent response, overriding the configured value for this run (default %d)", defaultMaxTokens))
+	// GitSynth never waits for input, so -ci changes nothing; it is accepted for scripts that pass it
+	flag.Bool("ci", false, "Run unattended. Accepted for compatibility: GitSynth always exits once the agent reports [ALL DONE] or stops")
+	noSummaries := flag.Bool("no-summaries", false, "Show truncated progress messages instead of summarizing them with the API")
+	skipPreflight := flag.Bool("skip-preflight", false, "Skip checking the API key and model access before starting")
+	backupDirFlag := flag.String("backup-dir", "", "Copy the working tree (without .git and node_modules) to this directory before the first modification, enabling the restore_all tool")
+	strictRegionsFlag := flag.Bool("strict-regions", false, "Refuse line edits outside the original conflict regions unless explicitly overridden")
+	strictTrackingFlag := flag.Bool("strict-tracking", false, "Refuse to edit or delete files that are not tracked by git")
+	editCap := flag.Int("edit-cap", defaultEditSoftCap, "Number of edits to a single file after which the agent is warned it may be thrashing (0 disables)")
+	compactThreshold := flag.Int("compact-threshold", defaultCompactThreshold, "Estimated token count past which older tool results are compacted")
+	viewMaxLines := flag.Int("view-max-lines", defaultViewFileMaxLines, "Number of lines above which view_file shows only the start and end of a whole file (0 disables)")
+	compactKeep := flag.Int("compact-keep", recentMessagesToKeep, "Number of most recent messages that are never compacted, at least 1")
+	retryBudget := flag.Duration("retry-budget", defaultRetryBudget, "Total time to keep retrying a failed API request before giving up")
+	trustRepoConfig := flag.Bool("trust-repo-config", false, "Run the test_command, allowed_commands, regenerators, and linters set in the repository's .gitsynth.yml (only use with branches you trust)")
+	toolTimeout := flag.Duration("tool-timeout", defaultToolTimeout, "Time a single tool call may run before it is abandoned (0 disables the limit)")
 	flag.Parse()
 
+	// Flags given explicitly, for settings whose flag defaults would otherwise hide the config
+	setFlags := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
+
 	// Load existing config
 	config, err := loadConfig()
 	if err != nil {
 		fmt.Printf("Error loading config: %v\n", err)
-		os.Exit(1)
+		os.Exit(ExitConfigError)
 	}
 
 	// If API key is provided via CLI, save it to config
@@ -185,25 +254,158 @@ func main() {
 		config.APIKey = *apiKeyFlag
 		if err := saveConfig(config); err != nil {
 			fmt.Printf("Error saving config: %v\n", err)
-			os.Exit(1)
+			os.Exit(ExitConfigError)
 		}
 	}
 
+	// If a base URL is provided via CLI, save it to config
+	if *baseURLFlag != "" {
+		if err := validateBaseURL(*baseURLFlag); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(ExitConfigError)
+		}
+		config.BaseURL = *baseURLFlag
+		if err := saveConfig(config); err != nil {
+			fmt.Printf("Error saving config: %v\n", err)
+			os.Exit(ExitConfigError)
+		}
+	}
+
+	// If a commit author is provided via CLI, save it to config
+	if *authorNameFlag != "" || *authorEmailFlag != "" {
+		if *authorNameFlag != "" {
+			config.AuthorName = *authorNameFlag
+		}
+		if *authorEmailFlag != "" {
+			config.AuthorEmail = *authorEmailFlag
+		}
+		if err := saveConfig(config); err != nil {
+			fmt.Printf("Error saving config: %v\n", err)
+			os.Exit(ExitConfigError)
+		}
+	}
+
+	if config.AuthorName != "" || config.AuthorEmail != "" {
+		if config.AuthorName == "" || config.AuthorEmail == "" {
+			fmt.Printf("Error: Both an author name and an author email are required to set a commit author.\n")
+			os.Exit(ExitConfigError)
+		}
+		commitAuthor = fmt.Sprintf("%s <%s>", config.AuthorName, config.AuthorEmail)
+	}
+
+	// Settings from the repository's .gitsynth.yml take precedence over the global config.
+	// The merged config is only used for this run and never saved.
+	runConfig := *config
+	repoConfig, repoConfigPath, err := loadRepoConfig(context.TODO())
+	if err != nil {
+		fmt.Printf("Error loading repository config: %v\n", err)
+		os.Exit(ExitConfigError)
+	}
+	skippedRepoKeys := mergeRepoConfig(&runConfig, repoConfig, *trustRepoConfig)
+
+	if runConfig.Regenerators != nil {
+		regenerators = runConfig.Regenerators
+	}
+	if runConfig.Linters != nil {
+		linters = runConfig.Linters
+	}
+	if runConfig.Strategies != nil {
+		mergeStrategies = runConfig.Strategies
+	}
+	repoIgnorePatterns = runConfig.Ignore
+	protectedPatterns = runConfig.Protected
+	testCommand = runConfig.TestCommand
+	allowedCommands = runConfig.AllowedCommands
+	strictTracking = *strictTrackingFlag
+	strictRegions = *strictRegionsFlag
+	backupDir = *backupDirFlag
+	editSoftCap = *editCap
+	viewFileMaxLines = *viewMaxLines
+	if !setFlags["view-max-lines"] && runConfig.ViewFileMaxLines > 0 {
+		viewFileMaxLines = runConfig.ViewFileMaxLines
+	}
+
+	// The -model flag overrides the configured model for this run only
+	modelName := runConfig.Model
+	if *modelFlag != "" {
+		modelName = *modelFlag
+	}
+	model, modelWarning := resolveModel(modelName, defaultModel)
+	if modelWarning != "" {
+		fmt.Printf("Warning: %s\n", modelWarning)
+	}
+	// Summaries default to the agent's model unless a separate one is configured
+	summaryModel, summaryModelWarning := resolveModel(runConfig.SummaryModel, model)
+	if summaryModelWarning != "" {
+		fmt.Printf("Warning: %s\n", summaryModelWarning)
+	}
+
+	// The -max-tokens flag likewise overrides the configured cap
+	maxTokens := runConfig.MaxTokens
+	if *maxTokensFlag > 0 {
+		maxTokens = *maxTokensFlag
+	}
+	if maxTokens <= 0 {
+		maxTokens = defaultMaxTokens
+	}
+
+	if err := validateCompactSettings(*compactThreshold, *compactKeep, &runConfig); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(ExitConfigError)
+	}
+
 	// Use API key from config or fail
 	if config.APIKey == "" {
 		configPath, _ := getConfigPath()
 		fmt.Printf("Error: No Anthropic API key found. Please provide one using the -api-key flag.\n")
 		fmt.Printf("The API key will be saved to %s for future use.\n", configPath)
-		os.Exit(1)
+		os.Exit(ExitConfigError)
 	}
 
-	apiKey := config.APIKey
-
 	// --- Initialize the client ---
-	client := anthropic.NewClient(option.WithAPIKey(apiKey))
+	// The same client backs both the agent and the logger's summarizer
+	options, err := clientOptions(config)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(ExitConfigError)
+	}
+	client := anthropic.NewClient(options...)
+
+	// --- Check credentials before touching any files ---
+	if !*skipPreflight {
+		if err := ValidateCredentials(context.TODO(), &client, model); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(exitCodeFor(err, nil))
+		}
+	}
+
+	// --- Snapshot conflict regions before any edits shift them ---
+	if strictRegions {
+		if err := snapshotConflictRegions(context.TODO()); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(ExitRuntimeErr)
+		}
+	}
+
+	// --- Record which files are conflicted to report progress against ---
+	if err := snapshotProgress(context.TODO()); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(ExitRuntimeErr)
+	}
 
 	// --- Initialize the logger ---
-	logger := NewGsLogger(*debugMode, &client)
+	summarizer := &client
+	if *noSummaries {
+		summarizer = nil
+	}
+	logger := NewGsLogger(*debugMode, *infoTools, summarizer, summaryModel)
+	if repoConfigPath != "" {
+		logger.Debug("Loaded repository config from %s\n", repoConfigPath)
+	}
+	if len(skippedRepoKeys) > 0 {
+		logger.Info("Ignoring %s from %s because they run commands; pass -trust-repo-config to use them\n",
+			strings.Join(skippedRepoKeys, ", "), repoConfigPath)
+	}
 	scanner := bufio.NewScanner(os.Stdin)
 	getUserMessage := func() (string, bool) {
 		if !scanner.Scan() {
@@ -224,20 +426,103 @@ func main() {
 		SeeGitStatusDefinition,
 		SearchSymbolDefinition,
 		FindReplaceAllDefinition,
+		IsTrackedDefinition,
+		PreviewFileSidesDefinition,
+		FindFilesDefinition,
+		ResolveIdenticalChunksDefinition,
+		ReviewResolutionDefinition,
+		MergeDiffstatDefinition,
+		NewValidateCredentialsDefinition(&client, model),
+		RegenerateFileDefinition,
+		GetMergeMessageDefinition,
+		ScanPlaceholdersDefinition,
+		CompareToBranchDefinition,
+		ReviewFileDefinition,
+		EditChunkPartDefinition,
+		CheckBalanceDefinition,
+		ResolveGeneratedFileDefinition,
+		ProgressDefinition,
+		ExportConflictsDefinition,
+		RunLinterDefinition,
+		ConflictProvenanceDefinition,
+		RestoreAllDefinition,
+		CheckReferencesDefinition,
+		ResolveUnionFilesDefinition,
+		PreviewMergeStrategyDefinition,
+		RepoSummaryDefinition,
+		ConsistencyCheckDefinition,
+		RestoreFileDefinition,
+		SearchInFileDefinition,
+		AbortMergeDefinition,
+		CreateFileDefinition,
+		MoveFileDefinition,
+		ConflictSummaryDefinition,
+		RunCommandDefinition,
+		CheckSyntaxDefinition,
+		AppendToFileDefinition,
+		PrependToFileDefinition,
+		ResolveChunkDefinition,
+		AcceptVersionDefinition,
+		SeeConflictDiffDefinition,
+		SeeBranchDiffDefinition,
+	}
+	logger.SetProgress(MergeProgress())
+	agent := NewAgent(&client, model, getUserMessage, tools, logger)
+	agent.maxTokens = maxTokens
+	agent.compactThreshold = *compactThreshold
+	agent.compactKeep = *compactKeep
+	if !setFlags["compact-threshold"] && runConfig.CompactThreshold > 0 {
+		agent.compactThreshold = runConfig.CompactThreshold
+	}
+	if !setFlags["compact-keep"] && runConfig.CompactKeep > 0 {
+		agent.compactKeep = runConfig.CompactKeep
+	}
+	agent.retryBudget = *retryBudget
+	if !setFlags["retry-budget"] && runConfig.RetryBudgetSeconds > 0 {
+		agent.retryBudget = time.Duration(runConfig.RetryBudgetSeconds) * time.Second
+	}
+	agent.toolTimeout = *toolTimeout
+	if !setFlags["tool-timeout"] && runConfig.ToolTimeoutSeconds > 0 {
+		agent.toolTimeout = time.Duration(runConfig.ToolTimeoutSeconds) * time.Second
 	}
-	agent := NewAgent(&client, getUserMessage, tools, logger)
 	runErr := agent.Run(context.TODO())
 	if runErr != nil {
 		logger.Error("%s", runErr.Error())
 	}
+	logger.Info("%s\n", agent.UsageSummary())
+
+	// Report any conflicts the agent left behind
+	unresolvedFiles, err := UnresolvedFiles(context.TODO())
+	if err != nil {
+		// Without the check the outcome is unknown, which must not be reported as success
+		logger.Error("Failed to check for unresolved conflicts: %v\n", err)
+		if runErr == nil {
+			runErr = fmt.Errorf("failed to check for unresolved conflicts: %w", err)
+		}
+	}
+	if runErr == nil && len(unresolvedFiles) > 0 {
+		logger.Error("%d file(s) still have unresolved conflicts:\n", len(unresolvedFiles))
+		for _, file := range unresolvedFiles {
+			logger.Info("  %s\n", file)
+		}
+	}
+
+	os.Exit(exitCodeFor(runErr, unresolvedFiles))
 }
 
-func NewAgent(client *anthropic.Client, getUserMessage func() (string, bool), tools []ToolDefinition, logger *GsLogger) *Agent {
+func NewAgent(client *anthropic.Client, model anthropic.Model, getUserMessage func() (string, bool), tools []ToolDefinition, logger *GsLogger) *Agent {
 	return &Agent{
 		client:         client,
 		getUserMessage: getUserMessage,
 		tools:          tools,
 		logger:         logger,
+		model:          model,
+		maxTokens:      defaultMaxTokens,
+		retryBudget:    defaultRetryBudget,
+		toolTimeout:    defaultToolTimeout,
+
+		compactThreshold: defaultCompactThreshold,
+		compactKeep:      recentMessagesToKeep,
 	}
 }
 
@@ -246,63 +531,138 @@ func (a *Agent) Run(ctx context.Context) error {
 
 	a.logger.Info("Welcome to GitSynth. Use 'ctrl-c' to quit at any time.\n")
 
-	userMessage := anthropic.NewUserMessage(anthropic.NewTextBlock(DefaultPrompt))
+	promptBlocks := []anthropic.ContentBlockParamUnion{anthropic.NewTextBlock(DefaultPrompt)}
+	if section := repoPromptSection(); section != "" {
+		promptBlocks = append(promptBlocks, anthropic.NewTextBlock(section))
+	}
+	userMessage := anthropic.NewUserMessage(promptBlocks...)
 	conversation = append(conversation, userMessage)
 
 	for {
-		finalMessage := &anthropic.Message{}
-		finalErr := error(nil)
-		maxRetries := 5
-		for retries := 0; retries < maxRetries; retries++ {
-			message, err := a.runInference(ctx, conversation)
-
-			if err != nil {
-				finalErr = err
-
-				var apiErr *anthropic.Error
-				if errors.As(err, &apiErr) {
-					// Exponentially retry non-fatal API errors and continue
-					backoffSeconds := (retries * retries) * (rand.Intn(3) + 2)
-					a.logger.Debug("API error occurred, retrying in %d seconds (attempt %d/%d): %v\n",
-						backoffSeconds, retries+1, maxRetries, err)
-					time.Sleep(time.Duration(backoffSeconds) * time.Second)
-					continue
-				} else { // Non-API errors are not retried
-					a.logger.Debug("Non-retryable error: %v\n", err)
-					break
+		// Proactively compact older tool results once the conversation grows past the threshold
+		if a.compactThreshold > 0 {
+			if tokens := estimateTokens(conversation); tokens > a.compactThreshold {
+				var compacted bool
+				conversation, compacted = compactConversation(conversation, a.compactKeep)
+				if compacted {
+					a.logger.Debug("Conversation estimated at %d tokens, compacted older tool results\n", tokens)
 				}
-			} else {
-				finalMessage = message
-				break
 			}
 		}
-		if finalErr != nil {
-			a.logger.Error("%s", finalErr.Error())
-			return finalErr
+
+		finalMessage, shrunkConversation, err := a.requestWithRetries(ctx, conversation)
+		if err != nil {
+			a.logger.Error("%s", err.Error())
+			return err
+		}
+		conversation = shrunkConversation
+		a.inputTokens += finalMessage.Usage.InputTokens
+		a.outputTokens += finalMessage.Usage.OutputTokens
+		if finalMessage.StopReason == anthropic.MessageStopReasonMaxTokens {
+			a.logger.Debug("Response stopped at the %d token limit and may be truncated, consider raising -max-tokens\n", a.maxTokens)
 		}
 		conversation = append(conversation, finalMessage.ToParam())
 
 		toolResults := []anthropic.ContentBlockParamUnion{}
+		allDone := false
 		for _, content := range finalMessage.Content {
 			switch content.Type {
 			case "text":
 				a.logger.AgentMessage(content.Text)
+				if strings.Contains(content.Text, allDoneSentinel) {
+					allDone = true
+				}
 			case "tool_use":
-				result := a.executeTool(content.ID, content.Name, content.Input)
+				result := a.executeTool(ctx, content.ID, content.Name, content.Input)
 				toolResults = append(toolResults, result)
 			}
 		}
-		if len(toolResults) == 0 {
+		if allDone {
 			// Done!
 			break
 		}
+		if len(toolResults) == 0 {
+			break
+		}
 		conversation = append(conversation, anthropic.NewUserMessage(toolResults...))
 	}
 
 	return nil
 }
 
-func (a *Agent) executeTool(id, name string, input json.RawMessage) anthropic.ContentBlockParamUnion {
+// UsageSummary describes the tokens used so far and their estimated cost
+func (a *Agent) UsageSummary() string {
+	summary := fmt.Sprintf("Used %s input / %s output tokens", formatCount(a.inputTokens), formatCount(a.outputTokens))
+	if cost, ok := estimateCost(a.model, a.inputTokens, a.outputTokens); ok {
+		summary += fmt.Sprintf(" (~$%.2f)", cost)
+	}
+	return summary
+}
+
+// requestWithRetries sends the conversation, retrying transient failures with backoff and trimming
+// old tool results once if the conversation outgrew the context window. It returns the response
+// and the conversation as it was finally sent.
+func (a *Agent) requestWithRetries(ctx context.Context, conversation []anthropic.MessageParam) (*anthropic.Message, []anthropic.MessageParam, error) {
+	finalMessage := &anthropic.Message{}
+	finalErr := error(nil)
+	maxRetries := 10
+	shrunk := false
+	retryStart := time.Now()
+	for retries := 0; retries < maxRetries; retries++ {
+		message, err := a.runInference(ctx, conversation)
+
+		if err != nil {
+			finalErr = err
+
+			// Trim old tool results and retry once if the conversation outgrew the context window
+			if isContextLengthError(err) {
+				if shrunk {
+					a.logger.Debug("Context length exceeded even after shrinking conversation\n")
+					break
+				}
+				var trimmed bool
+				conversation, trimmed = shrinkConversation(conversation, a.compactKeep)
+				if !trimmed {
+					a.logger.Debug("Context length exceeded and nothing left to trim\n")
+					break
+				}
+				shrunk = true
+				a.logger.Debug("Context length exceeded, retrying with older tool results trimmed\n")
+				continue
+			}
+
+			if isRetryableError(err) {
+				// Exponentially retry non-fatal API errors and interrupted streams, restarting the request
+				delay := retryDelay(retries)
+				if time.Since(retryStart)+delay > a.retryBudget {
+					a.logger.Debug("Giving up after retrying for %s: %v\n", time.Since(retryStart).Round(time.Second), err)
+					break
+				}
+				status := "transient error"
+				if described := describeAPIStatus(err); described != "" {
+					status = described
+				}
+				a.logger.Debug("Request failed (%s), retrying in %s (attempt %d/%d): %v\n",
+					status, delay.Round(time.Millisecond), retries+1, maxRetries, err)
+				time.Sleep(delay)
+				continue
+			} else { // Client errors and non-API errors are not retried
+				if status := describeAPIStatus(err); status != "" {
+					a.logger.Error("Request failed with status %s, not retrying\n", status)
+				}
+				a.logger.Debug("Non-retryable error: %v\n", err)
+				break
+			}
+		} else {
+			finalMessage = message
+			finalErr = nil
+			break
+		}
+	}
+	return finalMessage, conversation, finalErr
+}
+
+func (a *Agent) executeTool(ctx context.Context, id, name string, input json.RawMessage) anthropic.ContentBlockParamUnion {
 	var toolDef ToolDefinition
 	var found bool
 	for _, tool := range a.tools {
@@ -318,15 +678,79 @@ func (a *Agent) executeTool(id, name string, input json.RawMessage) anthropic.Co
 	}
 
 	a.logger.ToolCall(name, string(input))
-	response, err := toolDef.Function(input)
+
+	// Take the backup before anything is modified
+	if isMutatingTool(name) {
+		if err := ensureBackup(ctx); err != nil {
+			a.logger.ToolResult(name, err.Error(), true)
+			return anthropic.NewToolResultBlock(id, err.Error(), true)
+		}
+	}
+
+	response, err := a.callToolWithTimeout(ctx, toolDef, input)
 	if err != nil {
 		a.logger.ToolResult(name, err.Error(), true)
 		return anthropic.NewToolResultBlock(id, err.Error(), true)
 	}
 	a.logger.ToolResult(name, response, false)
+	if isMutatingTool(name) {
+		a.logger.SetProgress(MergeProgress())
+	}
 	return anthropic.NewToolResultBlock(id, response, false)
 }
 
+// callTool runs a tool's function, turning a panic into an error so one faulty tool cannot
+// crash the agent and lose the progress made so far
+func callTool(ctx context.Context, toolDef ToolDefinition, input json.RawMessage) (response string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			response = ""
+			err = fmt.Errorf("tool %s crashed: %v", toolDef.Name, r)
+		}
+	}()
+	return toolDef.Function(ctx, input)
+}
+
+// callToolWithTimeout runs a tool, returning an error if it does not finish within the agent's
+// tool timeout. Each call runs under its own context, so commands the tool starts with
+// toolCommand are killed, even after the call was abandoned; pure Go work is abandoned unless the
+// tool modifies files.
+func (a *Agent) callToolWithTimeout(ctx context.Context, toolDef ToolDefinition, input json.RawMessage) (string, error) {
+	if a.toolTimeout <= 0 {
+		return callTool(ctx, toolDef, input)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.toolTimeout)
+	defer cancel()
+
+	type toolOutcome struct {
+		response string
+		err      error
+	}
+	done := make(chan toolOutcome, 1)
+	go func() {
+		response, err := callTool(ctx, toolDef, input)
+		done <- toolOutcome{response, err}
+	}()
+
+	// Abandoning a tool that modifies files would let it keep writing behind the agent's back, so
+	// those are waited for; the commands they started are still killed once the time is up
+	if isMutatingTool(toolDef.Name) {
+		outcome := <-done
+		if outcome.err != nil && ctx.Err() != nil {
+			return "", fmt.Errorf("tool %s timed out after %s: %w", toolDef.Name, a.toolTimeout, outcome.err)
+		}
+		return outcome.response, outcome.err
+	}
+
+	select {
+	case outcome := <-done:
+		return outcome.response, outcome.err
+	case <-ctx.Done():
+		return "", fmt.Errorf("tool %s timed out after %s; try narrowing its scope", toolDef.Name, a.toolTimeout)
+	}
+}
+
 func (a *Agent) runInference(ctx context.Context, conversation []anthropic.MessageParam) (*anthropic.Message, error) {
 	anthropicTools := []anthropic.ToolUnionParam{}
 	for _, tool := range a.tools {
@@ -339,13 +763,13 @@ func (a *Agent) runInference(ctx context.Context, conversation []anthropic.Messa
 		})
 	}
 
-	message, err := a.client.Messages.New(ctx, anthropic.MessageNewParams{
-		Model:     anthropic.ModelClaude3_5SonnetLatest,
-		MaxTokens: int64(1024),
+	// Stream the response so the agent's reasoning shows up as it is written
+	return streamMessage(ctx, a.client, anthropic.MessageNewParams{
+		Model:     a.model,
+		MaxTokens: a.maxTokens,
 		Messages:  conversation,
 		Tools:     anthropicTools,
-	})
-	return message, err
+	}, a.logger.AgentStream)
 }
 
 func GenerateSchema[T any]() anthropic.ToolInputSchemaParam {
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+)
+
+// stubResponse is one canned reply from the stub API server: an error status and body, or a
+// streamed assistant message with the given text, or a call to the given tool, when status is 0
+type stubResponse struct {
+	status int
+	body   string
+	text   string
+	tool   string
+}
+
+// stubAPI serves canned responses in order and records the request bodies it received
+type stubAPI struct {
+	mu        sync.Mutex
+	responses []stubResponse
+	requests  []string
+}
+
+func (s *stubAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, string(body))
+	id := len(s.requests)
+	if len(s.responses) == 0 {
+		s.mu.Unlock()
+		http.Error(w, `{"type":"error","error":{"type":"api_error","message":"no more stub responses"}}`, http.StatusInternalServerError)
+		return
+	}
+	response := s.responses[0]
+	s.responses = s.responses[1:]
+	s.mu.Unlock()
+
+	if response.status != 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(response.status)
+		io.WriteString(w, response.body)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	block := `{"type":"text","text":""}`
+	delta := fmt.Sprintf(`{"type":"text_delta","text":%q}`, response.text)
+	stopReason := "end_turn"
+	if response.tool != "" {
+		block = fmt.Sprintf(`{"type":"tool_use","id":"toolu_%d","name":%q,"input":{}}`, id, response.tool)
+		delta = `{"type":"input_json_delta","partial_json":"{}"}`
+		stopReason = "tool_use"
+	}
+	events := [][2]string{
+		{"message_start", `{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","model":"stub","content":[],"stop_reason":null,"usage":{"input_tokens":10,"output_tokens":1}}}`},
+		{"content_block_start", `{"type":"content_block_start","index":0,"content_block":` + block + `}`},
+		{"content_block_delta", `{"type":"content_block_delta","index":0,"delta":` + delta + `}`},
+		{"content_block_stop", `{"type":"content_block_stop","index":0}`},
+		{"message_delta", `{"type":"message_delta","delta":{"stop_reason":"` + stopReason + `"},"usage":{"output_tokens":5}}`},
+		{"message_stop", `{"type":"message_stop"}`},
+	}
+	for _, event := range events {
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event[0], event[1])
+	}
+}
+
+func (s *stubAPI) requestCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+// newStubAgent returns an agent whose client talks to a stub server serving the given responses
+func newStubAgent(t *testing.T, responses ...stubResponse) (*Agent, *stubAPI) {
+	t.Helper()
+	api := &stubAPI{responses: responses}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	client := anthropic.NewClient(
+		option.WithAPIKey("test-key"),
+		option.WithBaseURL(server.URL),
+		option.WithMaxRetries(0),
+	)
+	logger := NewGsLogger(false, false, nil, "stub")
+	agent := NewAgent(&client, "stub", func() (string, bool) { return "", false }, nil, logger)
+	return agent, api
+}
+
+// conversationWithToolResult returns a conversation whose second message is an old tool result
+func conversationWithToolResult(output string) []anthropic.MessageParam {
+	return []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock("prompt")),
+		anthropic.NewAssistantMessage(anthropic.ContentBlockParamUnion{OfRequestToolUseBlock: &anthropic.ToolUseBlockParam{
+			ID: "tool_1", Name: "view_file", Input: map[string]any{},
+		}}),
+		anthropic.NewUserMessage(anthropic.NewToolResultBlock("tool_1", output, false)),
+		anthropic.NewAssistantMessage(anthropic.NewTextBlock("thinking")),
+		anthropic.NewUserMessage(anthropic.NewTextBlock("continue")),
+	}
+}
+
+func TestRequestWithRetriesSucceedsAfterShrinking(t *testing.T) {
+	agent, api := newStubAgent(t,
+		stubResponse{status: http.StatusBadRequest, body: `{"type":"error","error":{"type":"invalid_request_error","message":"prompt is too long: 250000 tokens > 200000 maximum"}}`},
+		stubResponse{text: "done"},
+	)
+	agent.compactKeep = 2
+
+	message, conversation, err := agent.requestWithRetries(context.Background(), conversationWithToolResult("a very long tool output"))
+	if err != nil {
+		t.Fatalf("requestWithRetries() error = %v", err)
+	}
+	if len(message.Content) != 1 || message.Content[0].Text != "done" {
+		t.Errorf("message content = %+v, want the text \"done\"", message.Content)
+	}
+	if got := api.requestCount(); got != 2 {
+		t.Fatalf("requests = %d, want 2", got)
+	}
+	if strings.Contains(api.requests[1], "a very long tool output") {
+		t.Errorf("retried request still contains the old tool result")
+	}
+	if got := conversation[2].Content[0].OfRequestToolResultBlock.Content[0].OfRequestTextBlock.Text; got != elidedToolResult {
+		t.Errorf("returned conversation tool result = %q, want it elided", got)
+	}
+}
+
+func TestRequestWithRetriesGivesUpWhenNothingToShrink(t *testing.T) {
+	agent, api := newStubAgent(t,
+		stubResponse{status: http.StatusBadRequest, body: `{"type":"error","error":{"type":"invalid_request_error","message":"prompt is too long: 250000 tokens > 200000 maximum"}}`},
+		stubResponse{text: "done"},
+	)
+
+	_, _, err := agent.requestWithRetries(context.Background(), []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock("prompt")),
+	})
+	if err == nil {
+		t.Fatal("requestWithRetries() succeeded, want the context length error")
+	}
+	if got := api.requestCount(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestRunStopsWhenAgentMakesNoToolCalls(t *testing.T) {
+	agent, api := newStubAgent(t, stubResponse{text: "I could not finish."}, stubResponse{text: "[ALL DONE]"})
+	agent.getUserMessage = func() (string, bool) {
+		t.Error("Run() asked for user input")
+		return "", false
+	}
+
+	if err := agent.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := api.requestCount(); got != 1 {
+		t.Errorf("requests = %d, want Run to stop after the first response", got)
+	}
+}
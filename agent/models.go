@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// Model used by the agent when none is configured
+const defaultModel = anthropic.ModelClaude3_5SonnetLatest
+
+// Response token cap used when none is configured. Large conflict chunks need room for
+// both the reasoning and the full new_content of an edit.
+const defaultMaxTokens = 4096
+
+// Models known to work with GitSynth's tool use
+var knownModels = []anthropic.Model{
+	anthropic.ModelClaude3_7SonnetLatest,
+	anthropic.ModelClaude3_7Sonnet20250219,
+	anthropic.ModelClaude3_5SonnetLatest,
+	anthropic.ModelClaude3_5Sonnet20241022,
+	anthropic.ModelClaude3_5HaikuLatest,
+	anthropic.ModelClaude3_5Haiku20241022,
+	anthropic.ModelClaude3OpusLatest,
+	anthropic.ModelClaude_3_Opus_20240229,
+}
+
+// resolveModel returns the named model, or the fallback with a warning if the name is not a known model.
+// An empty name selects the fallback silently.
+func resolveModel(name string, fallback anthropic.Model) (anthropic.Model, string) {
+	if name == "" {
+		return fallback, ""
+	}
+	for _, model := range knownModels {
+		if string(model) == name {
+			return model, ""
+		}
+	}
+	return fallback, fmt.Sprintf("Unknown model %q, falling back to %s", name, fallback)
+}
+
+// modelPrice is the cost in US dollars per million tokens
+type modelPrice struct {
+	input  float64
+	output float64
+}
+
+// Published prices used for cost estimates
+var modelPrices = map[anthropic.Model]modelPrice{
+	anthropic.ModelClaude3_7SonnetLatest:   {input: 3, output: 15},
+	anthropic.ModelClaude3_7Sonnet20250219: {input: 3, output: 15},
+	anthropic.ModelClaude3_5SonnetLatest:   {input: 3, output: 15},
+	anthropic.ModelClaude3_5Sonnet20241022: {input: 3, output: 15},
+	anthropic.ModelClaude3_5HaikuLatest:    {input: 0.8, output: 4},
+	anthropic.ModelClaude3_5Haiku20241022:  {input: 0.8, output: 4},
+	anthropic.ModelClaude3OpusLatest:       {input: 15, output: 75},
+	anthropic.ModelClaude_3_Opus_20240229:  {input: 15, output: 75},
+}
+
+// estimateCost returns the approximate cost of a run in US dollars, and false if the model's price is unknown
+func estimateCost(model anthropic.Model, inputTokens, outputTokens int64) (float64, bool) {
+	price, ok := modelPrices[model]
+	if !ok {
+		return 0, false
+	}
+	return (float64(inputTokens)*price.input + float64(outputTokens)*price.output) / 1e6, true
+}
+
+// formatCount formats a number with thousands separators, e.g. 48210 as "48,210"
+func formatCount(n int64) string {
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+	digits := strconv.FormatInt(n, 10)
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + "," + digits[i:]
+	}
+	return digits
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"os"
+	"sync"
+)
+
+// Files that had conflicts when the run started, used to measure progress
+var (
+	conflictedAtStart   []string
+	conflictedAtStartMu sync.Mutex
+)
+
+// snapshotProgress records the files that are conflicted before the agent starts
+func snapshotProgress(ctx context.Context) error {
+	files, err := ListUnmergedFiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	conflictedAtStartMu.Lock()
+	defer conflictedAtStartMu.Unlock()
+	conflictedAtStart = files
+	return nil
+}
+
+// MergeProgress returns how many of the initially conflicted files no longer contain
+// conflict markers, out of the total. Deleted files count as resolved.
+func MergeProgress() (resolved int, total int) {
+	conflictedAtStartMu.Lock()
+	files := append([]string(nil), conflictedAtStart...)
+	conflictedAtStartMu.Unlock()
+
+	for _, file := range files {
+		if _, err := os.Stat(file); os.IsNotExist(err) {
+			resolved++
+			continue
+		}
+		if hasConflicts, err := HasMergeConflicts(file); err == nil && !hasConflicts {
+			resolved++
+		}
+	}
+	return resolved, len(files)
+}
+
+// UnresolvedFiles lists the files git still reports as unmerged, plus any initially
+// conflicted files that still contain conflict markers even though they were staged or committed
+func UnresolvedFiles(ctx context.Context) ([]string, error) {
+	unmerged, err := ListUnmergedFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conflictedAtStartMu.Lock()
+	files := append([]string(nil), conflictedAtStart...)
+	conflictedAtStartMu.Unlock()
+
+	seen := setOf(unmerged...)
+	for _, file := range files {
+		if seen[file] {
+			continue
+		}
+		if hasConflicts, err := HasMergeConflicts(file); err == nil && hasConflicts {
+			unmerged = append(unmerged, file)
+			seen[file] = true
+		}
+	}
+	return unmerged, nil
+}
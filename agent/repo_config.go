@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Per-repository config files, looked up at the repository root in this order
+var repoConfigFiles = []string{".gitsynth.yml", ".gitsynth.yaml"}
+
+// RepoConfig holds repository-specific settings from .gitsynth.yml. Its settings take
+// precedence over the global config; credentials and commit identity are global only.
+type RepoConfig struct {
+	// Ignore lists glob patterns for files that searching and listing tools skip
+	Ignore []string `yaml:"ignore"`
+
+	// Protected lists glob patterns for files the agent must not modify
+	Protected []string `yaml:"protected"`
+
+	// Strategies maps glob patterns to the side ("ours", "theirs", or "union") that should win their conflicts
+	Strategies map[string]string `yaml:"strategies"`
+
+	// TestCommand is the command that verifies the repository builds and passes its tests
+	TestCommand string `yaml:"test_command"`
+
+	// AllowedCommands lists command prefixes the run_command tool may run besides the test command
+	AllowedCommands []string `yaml:"allowed_commands"`
+
+	Regenerators map[string]string `yaml:"regenerators"`
+	Linters      map[string]string `yaml:"linters"`
+}
+
+// loadRepoConfig reads the repository's .gitsynth.yml, returning nil if there is none
+func loadRepoConfig(ctx context.Context) (*RepoConfig, string, error) {
+	root, err := ExecuteGitCommand(ctx, "rev-parse", "--show-toplevel")
+	if err != nil {
+		// Not in a repository, so there is nothing to discover
+		return nil, "", nil
+	}
+
+	for _, name := range repoConfigFiles {
+		path := filepath.Join(root, name)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, path, fmt.Errorf("failed to read repository config file: %w", err)
+		}
+
+		var repoConfig RepoConfig
+		if err := yaml.Unmarshal(data, &repoConfig); err != nil {
+			return nil, path, fmt.Errorf("failed to parse repository config file %s: %w", path, err)
+		}
+		for pattern, strategy := range repoConfig.Strategies {
+			if strategy != "ours" && strategy != "theirs" && strategy != "union" {
+				return nil, path, fmt.Errorf("invalid strategy %q for %q in %s: must be ours, theirs, or union", strategy, pattern, path)
+			}
+		}
+		return &repoConfig, path, nil
+	}
+	return nil, "", nil
+}
+
+// mergeRepoConfig applies repository settings over the global config. Lists are combined,
+// and for maps and single values the repository's setting wins. Settings that run commands
+// are skipped unless trustCommands is set; the keys that were skipped are returned.
+func mergeRepoConfig(config *Config, repoConfig *RepoConfig, trustCommands bool) []string {
+	if repoConfig == nil {
+		return nil
+	}
+
+	config.Ignore = append(config.Ignore, repoConfig.Ignore...)
+	config.Protected = append(config.Protected, repoConfig.Protected...)
+	config.Strategies = mergeStringMaps(config.Strategies, repoConfig.Strategies)
+
+	// A branch being merged can change .gitsynth.yml, so its shell commands are not run unless trusted
+	if !trustCommands {
+		var skipped []string
+		if repoConfig.TestCommand != "" {
+			skipped = append(skipped, "test_command")
+		}
+		if len(repoConfig.AllowedCommands) > 0 {
+			skipped = append(skipped, "allowed_commands")
+		}
+		if len(repoConfig.Regenerators) > 0 {
+			skipped = append(skipped, "regenerators")
+		}
+		if len(repoConfig.Linters) > 0 {
+			skipped = append(skipped, "linters")
+		}
+		return skipped
+	}
+
+	config.AllowedCommands = append(config.AllowedCommands, repoConfig.AllowedCommands...)
+	config.Regenerators = mergeStringMaps(config.Regenerators, repoConfig.Regenerators)
+	config.Linters = mergeStringMaps(config.Linters, repoConfig.Linters)
+	if repoConfig.TestCommand != "" {
+		config.TestCommand = repoConfig.TestCommand
+	}
+	return nil
+}
+
+// mergeStringMaps returns base with the entries of override added, replacing existing keys
+func mergeStringMaps(base, override map[string]string) map[string]string {
+	if len(override) == 0 {
+		return base
+	}
+
+	merged := make(map[string]string, len(base)+len(override))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range override {
+		merged[key] = value
+	}
+	return merged
+}
+
+// Settings applied from the merged global and repository config
+var (
+	repoIgnorePatterns []string
+	protectedPatterns  []string
+	mergeStrategies    = map[string]string{}
+	testCommand        = ""
+)
+
+// matchesAnyPattern reports whether a path matches one of the glob patterns (see matchGlob)
+func matchesAnyPattern(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkProtected returns an error if the repository config protects a path from modification
+func checkProtected(path string) error {
+	if matchesAnyPattern(protectedPatterns, path) {
+		return fmt.Errorf("refusing to modify %s: it is protected by the repository's GitSynth config", path)
+	}
+	return nil
+}
+
+// repoPromptSection describes repository-specific settings for the agent, or is empty if there are none
+func repoPromptSection() string {
+	var lines []string
+
+	if len(mergeStrategies) > 0 {
+		patterns := make([]string, 0, len(mergeStrategies))
+		for pattern := range mergeStrategies {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+
+		lines = append(lines, "- Resolve conflicts in files matching these patterns using the given strategy (preview_merge_strategy shows the result):")
+		for _, pattern := range patterns {
+			lines = append(lines, fmt.Sprintf("  - %s: %s", pattern, mergeStrategies[pattern]))
+		}
+	}
+	if len(protectedPatterns) > 0 {
+		lines = append(lines, fmt.Sprintf("- Never modify files matching: %s", strings.Join(protectedPatterns, ", ")))
+	}
+	if testCommand != "" {
+		lines = append(lines, fmt.Sprintf("- The repository's test command is: %s", testCommand))
+	}
+	if len(allowedCommands) > 0 {
+		lines = append(lines, fmt.Sprintf("- run_command may run commands starting with: %s", strings.Join(allowedCommands, ", ")))
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return "📁 **Repository Configuration** (from .gitsynth.yml)\n\n" + strings.Join(lines, "\n")
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeRepoConfigCommands(t *testing.T) {
+	repoConfig := &RepoConfig{
+		Ignore:          []string{"*.min.js"},
+		TestCommand:     "make test",
+		AllowedCommands: []string{"make"},
+		Regenerators:    map[string]string{"*.pb.go": "go generate ./..."},
+		Linters:         map[string]string{"*.go": "golangci-lint run"},
+	}
+	global := Config{TestCommand: "go test ./...", Linters: map[string]string{"*.py": "ruff check"}}
+
+	untrusted := global
+	skipped := mergeRepoConfig(&untrusted, repoConfig, false)
+	if want := []string{"test_command", "allowed_commands", "regenerators", "linters"}; !reflect.DeepEqual(skipped, want) {
+		t.Errorf("skipped keys = %v, want %v", skipped, want)
+	}
+	if untrusted.TestCommand != "go test ./..." || len(untrusted.AllowedCommands) != 0 ||
+		len(untrusted.Regenerators) != 0 || !reflect.DeepEqual(untrusted.Linters, global.Linters) {
+		t.Errorf("untrusted repository config changed command settings: %+v", untrusted)
+	}
+	if !reflect.DeepEqual(untrusted.Ignore, []string{"*.min.js"}) {
+		t.Errorf("Ignore = %v, want the repository patterns applied", untrusted.Ignore)
+	}
+
+	trusted := global
+	if skipped := mergeRepoConfig(&trusted, repoConfig, true); len(skipped) != 0 {
+		t.Errorf("skipped keys = %v with a trusted config, want none", skipped)
+	}
+	if trusted.TestCommand != "make test" || !reflect.DeepEqual(trusted.AllowedCommands, []string{"make"}) ||
+		trusted.Regenerators["*.pb.go"] == "" || len(trusted.Linters) != 2 {
+		t.Errorf("trusted repository config not applied: %+v", trusted)
+	}
+}
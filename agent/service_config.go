@@ -3,14 +3,55 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+
+	"github.com/anthropics/anthropic-sdk-go/option"
 )
 
 const configFile = ".gitsynth"
 
 type Config struct {
-	APIKey string `json:"api_key"`
+	APIKey      string `json:"api_key"`
+	AuthorName  string `json:"author_name,omitempty"`
+	AuthorEmail string `json:"author_email,omitempty"`
+	BaseURL     string `json:"base_url,omitempty"`
+
+	// Model drives the agent; SummaryModel summarizes progress messages and can be a cheaper model
+	Model        string `json:"model,omitempty"`
+	SummaryModel string `json:"summary_model,omitempty"`
+
+	// MaxTokens caps each agent response; zero uses defaultMaxTokens
+	MaxTokens int64 `json:"max_tokens,omitempty"`
+
+	// Conversation compaction settings; zero uses the defaults. The -compact-* flags take precedence.
+	CompactThreshold int `json:"compact_threshold,omitempty"`
+	CompactKeep      int `json:"compact_keep,omitempty"`
+
+	// RetryBudgetSeconds bounds how long a failed API request is retried; zero uses defaultRetryBudget
+	RetryBudgetSeconds int `json:"retry_budget_seconds,omitempty"`
+
+	// ViewFileMaxLines is the line count above which view_file elides the middle of a file; zero uses the default
+	ViewFileMaxLines int `json:"view_file_max_lines,omitempty"`
+
+	// ToolTimeoutSeconds bounds a single tool call; zero uses defaultToolTimeout
+	ToolTimeoutSeconds int `json:"tool_timeout_seconds,omitempty"`
+
+	// Regenerators maps file name glob patterns to commands that regenerate matching files
+	Regenerators map[string]string `json:"regenerators,omitempty"`
+
+	// Linters maps file name glob patterns to linter commands run on matching files
+	Linters map[string]string `json:"linters,omitempty"`
+
+	// Repository-level settings, usually supplied by .gitsynth.yml (see RepoConfig)
+	Ignore      []string          `json:"ignore,omitempty"`
+	Protected   []string          `json:"protected,omitempty"`
+	Strategies  map[string]string `json:"strategies,omitempty"`
+	TestCommand string            `json:"test_command,omitempty"`
+
+	// AllowedCommands lists command prefixes the run_command tool may run besides the test command
+	AllowedCommands []string `json:"allowed_commands,omitempty"`
 }
 
 func getConfigPath() (string, error) {
@@ -43,6 +84,30 @@ func loadConfig() (*Config, error) {
 	return &config, nil
 }
 
+// validateBaseURL checks that a custom API base URL is an absolute http(s) URL
+func validateBaseURL(baseURL string) error {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid base URL %q: must be an absolute http or https URL", baseURL)
+	}
+	return nil
+}
+
+// clientOptions returns the API client options for a config: its API key and custom base URL, if any
+func clientOptions(config *Config) ([]option.RequestOption, error) {
+	options := []option.RequestOption{option.WithAPIKey(config.APIKey)}
+	if config.BaseURL != "" {
+		if err := validateBaseURL(config.BaseURL); err != nil {
+			return nil, err
+		}
+		options = append(options, option.WithBaseURL(config.BaseURL))
+	}
+	return options, nil
+}
+
 func saveConfig(config *Config) error {
 	configPath, err := getConfigPath()
 	if err != nil {
@@ -59,4 +124,4 @@ func saveConfig(config *Config) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+)
+
+func TestClientOptionsUseBaseURL(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if got := r.Header.Get("X-Api-Key"); got != "test-key" {
+			t.Errorf("X-Api-Key = %q, want the configured key", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"id":"msg_1","type":"message","role":"assistant","model":"stub","content":[],"stop_reason":"end_turn","usage":{"input_tokens":1,"output_tokens":1}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	options, err := clientOptions(&Config{APIKey: "test-key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("clientOptions() error = %v", err)
+	}
+	client := anthropic.NewClient(append(options, option.WithMaxRetries(0))...)
+	if err := ValidateCredentials(context.Background(), &client, "stub"); err != nil {
+		t.Fatalf("ValidateCredentials() error = %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests to the base URL = %d, want 1", got)
+	}
+}
+
+func TestClientOptionsRejectInvalidBaseURL(t *testing.T) {
+	if _, err := clientOptions(&Config{APIKey: "test-key", BaseURL: "localhost:8080"}); err == nil {
+		t.Error("clientOptions() accepted a base URL without a scheme, want an error")
+	}
+}
@@ -24,7 +24,9 @@ type EphemeralLogEntry struct {
 // GsLogger is a logger that handles permanent and ephemeral logs with summarization
 type GsLogger struct {
 	debugMode bool
-	client    *anthropic.Client
+	infoTools bool              // Print a permanent line for each mutating tool call
+	client    *anthropic.Client // Summarizer client; nil disables summarization
+	model     anthropic.Model   // Model used for summaries
 	spinner   *spinner.Spinner
 
 	// Mutex for thread-safe console output
@@ -53,14 +55,17 @@ var (
 )
 
 // NewGsLogger creates a new enhanced logger
-func NewGsLogger(debugMode bool, client *anthropic.Client) *GsLogger {
+// A nil client disables summarization, and messages are shown truncated instead
+func NewGsLogger(debugMode bool, infoTools bool, client *anthropic.Client, model anthropic.Model) *GsLogger {
 	// Configure spinner
 	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
 	s.Color("cyan")
 
 	logger := &GsLogger{
 		debugMode:       debugMode,
+		infoTools:       infoTools,
 		client:          client,
+		model:           model,
 		spinner:         s,
 		ephemeralQueue:  make(chan EphemeralLogEntry, 100),
 		hasEphemeralLog: false,
@@ -166,8 +171,22 @@ func (l *GsLogger) AgentMessage(msg string) {
 	}()
 }
 
+// AgentStream shows the end of a partial agent message while it is still being streamed.
+// The complete message is later passed to AgentMessage for summarization.
+func (l *GsLogger) AgentStream(text string) {
+	message := []rune(strings.ReplaceAll(text, "\n", " "))
+	if limit := l.maxLineLength - 4; len(message) > limit {
+		message = append([]rune("..."), message[len(message)-limit+3:]...)
+	}
+	l.showEphemeralLog("💭" + string(message))
+}
+
 // ToolCall queues a tool call to be summarized and displayed
 func (l *GsLogger) ToolCall(name, input string) {
+	if line, ok := toolInfoLine(l.infoTools, name, input); ok {
+		l.toolInfo(line)
+	}
+
 	// Create channel for the summary callback
 	callbackCh := make(chan string, 1)
 
@@ -221,6 +240,33 @@ func (l *GsLogger) ToolResult(name, result string, isError bool) {
 	}()
 }
 
+// SetProgress shows how many conflicted files are resolved next to the spinner
+func (l *GsLogger) SetProgress(resolved, total int) {
+	if total == 0 {
+		return
+	}
+
+	l.spinner.Lock()
+	l.spinner.Suffix = fmt.Sprintf(" Resolving (%d/%d files)", resolved, total)
+	l.spinner.Unlock()
+}
+
+// toolInfo prints a permanent, concise line describing a mutating tool call
+func (l *GsLogger) toolInfo(line string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Stop spinner, clear any ephemeral log
+	l.clearDisplay()
+
+	// Print permanent message on its own line
+	infoColor.Println(l.sanitizeMessage("✏️ " + line))
+
+	// Reset ephemeral log state and restart spinner
+	l.hasEphemeralLog = false
+	l.spinner.Start()
+}
+
 // clearDisplay stops the spinner and clears any ephemeral log
 // Must be called with the mutex locked
 func (l *GsLogger) clearDisplay() {
@@ -288,6 +334,11 @@ func (l *GsLogger) summarizeText(text string) string {
 		return text
 	}
 
+	// Without a client, show the text truncated locally
+	if l.client == nil {
+		return l.sanitizeMessage(text)
+	}
+
 	prompt := fmt.Sprintf(
 		"Please summarize the following text in a brief, user-friendly way (max 150 chars). IMPORTANT: Use a single line with no line breaks:\n\n%s",
 		text,
@@ -297,7 +348,7 @@ func (l *GsLogger) summarizeText(text string) string {
 	defer cancel()
 
 	message, err := l.client.Messages.New(ctx, anthropic.MessageNewParams{
-		Model:     anthropic.ModelClaude3_5SonnetLatest,
+		Model:     l.model,
 		MaxTokens: int64(150),
 		Messages: []anthropic.MessageParam{
 			anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+func TestGsLoggerWithoutClient(t *testing.T) {
+	var output syncBuffer
+	previous := color.Output
+	color.Output = &output
+	t.Cleanup(func() { color.Output = previous })
+
+	logger := NewGsLogger(false, false, nil, "stub")
+	t.Cleanup(logger.spinner.Stop)
+
+	// Long enough to be summarized if there were a client
+	message := "Resolved the conflict in parser.go by keeping both import blocks. " + strings.Repeat("More detail. ", 20)
+	if got, want := logger.summarizeText(message), logger.sanitizeMessage(message); got != want {
+		t.Errorf("summarizeText() = %q, want the message truncated locally %q", got, want)
+	}
+
+	logger.AgentMessage(message)
+	logger.ToolResult("view_file", strings.Repeat("line of output\n", 20), false)
+	logger.Info("finished\n")
+	time.Sleep(50 * time.Millisecond)
+
+	got := output.String()
+	for _, want := range []string{"💭Resolved the conflict in parser.go", "✅ Result: line of output", "finished"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output = %q, want it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "Summary failed") {
+		t.Errorf("output = %q, want no summarization attempt without a client", got)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// fileSnapshot is a file's contents before it was first edited; missing is set if it did not exist yet
+type fileSnapshot struct {
+	content []byte
+	missing bool
+}
+
+// Pre-edit snapshots of each file modified since the last git_save_changes, used by restore_file
+var (
+	fileSnapshots   = make(map[string]fileSnapshot)
+	fileSnapshotsMu sync.Mutex
+)
+
+// snapshotFile records a file's current contents unless it was already snapshotted.
+// Call it before every write; only the first call per file since the last save has an effect.
+func snapshotFile(path string) {
+	fileSnapshotsMu.Lock()
+	defer fileSnapshotsMu.Unlock()
+
+	path = filepath.Clean(path)
+	if _, ok := fileSnapshots[path]; ok {
+		return
+	}
+
+	// Unreadable files are not snapshotted, since they could not be restored faithfully
+	content, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		fileSnapshots[path] = fileSnapshot{content: content}
+	case os.IsNotExist(err):
+		fileSnapshots[path] = fileSnapshot{missing: true}
+	}
+}
+
+// restoreSnapshot writes a file's snapshot back and forgets it. Files that did not exist are removed.
+func restoreSnapshot(path string) error {
+	fileSnapshotsMu.Lock()
+	defer fileSnapshotsMu.Unlock()
+
+	path = filepath.Clean(path)
+	snapshot, ok := fileSnapshots[path]
+	if !ok {
+		return fmt.Errorf("no snapshot of %s: it has not been modified since the last save", path)
+	}
+
+	if snapshot.missing {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	} else {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to recreate directory for %s: %w", path, err)
+		}
+		if err := os.WriteFile(path, snapshot.content, 0644); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", path, err)
+		}
+	}
+
+	delete(fileSnapshots, path)
+	return nil
+}
+
+// snapshottedFiles lists the files that currently have a snapshot, sorted
+func snapshottedFiles() []string {
+	fileSnapshotsMu.Lock()
+	defer fileSnapshotsMu.Unlock()
+
+	files := make([]string, 0, len(fileSnapshots))
+	for path := range fileSnapshots {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// clearSnapshots forgets every snapshot, e.g. once changes are committed
+func clearSnapshots() {
+	fileSnapshotsMu.Lock()
+	defer fileSnapshotsMu.Unlock()
+
+	fileSnapshots = make(map[string]fileSnapshot)
+}
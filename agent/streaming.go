@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// streamInterruptedError marks a stream that failed after it was opened, e.g. a dropped
+// connection or an overloaded error event, so the whole request can be retried
+type streamInterruptedError struct {
+	err error
+}
+
+func (e *streamInterruptedError) Error() string {
+	return "stream interrupted: " + e.err.Error()
+}
+
+func (e *streamInterruptedError) Unwrap() error {
+	return e.err
+}
+
+// Backoff settings for retrying failed inference requests
+var (
+	minRetryDelay = 2 * time.Second
+	maxRetryDelay = 60 * time.Second
+)
+
+// defaultRetryBudget is how long a request is retried before giving up
+const defaultRetryBudget = 5 * time.Minute
+
+// statusOverloaded is the status code the API returns when it is temporarily overloaded
+const statusOverloaded = 529
+
+// isRetryableError reports whether a failed inference request is worth restarting. Rate limits,
+// overload, server errors and interrupted streams are transient; other API errors such as a bad
+// request or an invalid key fail the same way every time.
+func isRetryableError(err error) bool {
+	var apiErr *anthropic.Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout, statusOverloaded:
+			return true
+		}
+		return false
+	}
+	var streamErr *streamInterruptedError
+	return errors.As(err, &streamErr)
+}
+
+// describeAPIStatus names the status of a failed API request, or returns "" for other errors
+func describeAPIStatus(err error) string {
+	var apiErr *anthropic.Error
+	if !errors.As(err, &apiErr) {
+		return ""
+	}
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests:
+		return "429 rate limited"
+	case statusOverloaded:
+		return "529 overloaded"
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Sprintf("%d %s, check the API key and its permissions", apiErr.StatusCode, http.StatusText(apiErr.StatusCode))
+	}
+	return fmt.Sprintf("%d %s", apiErr.StatusCode, http.StatusText(apiErr.StatusCode))
+}
+
+// retryDelay returns how long to wait before the given zero-based retry attempt: exponential
+// backoff with full jitter, between minRetryDelay and minRetryDelay*2^attempt capped at maxRetryDelay
+func retryDelay(attempt int) time.Duration {
+	ceiling := maxRetryDelay
+	if attempt < 16 && minRetryDelay<<attempt < ceiling {
+		ceiling = minRetryDelay << attempt
+	}
+	return minRetryDelay + time.Duration(rand.Int63n(int64(ceiling-minRetryDelay)+1))
+}
+
+// streamMessage sends a request with the streaming API, passing the text produced so far to
+// onText as it arrives. Tool use blocks are accumulated until the message is complete.
+func streamMessage(ctx context.Context, client *anthropic.Client, params anthropic.MessageNewParams, onText func(string)) (*anthropic.Message, error) {
+	stream := client.Messages.NewStreaming(ctx, params)
+	defer stream.Close()
+
+	message := anthropic.Message{}
+	text := ""
+	for stream.Next() {
+		event := stream.Current()
+
+		switch event := event.AsAny().(type) {
+		case anthropic.ContentBlockDeltaEvent:
+			if event.Delta.Type == "text_delta" && onText != nil {
+				text += event.Delta.Text
+				onText(text)
+			}
+		case anthropic.ContentBlockStopEvent:
+			// A tool called without arguments can stream an empty input, which is not valid JSON
+			if len(message.Content) > 0 {
+				block := &message.Content[len(message.Content)-1]
+				if block.Type == "tool_use" && len(block.Input) == 0 {
+					block.Input = json.RawMessage("{}")
+				}
+			}
+		}
+
+		if err := message.Accumulate(event); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := stream.Err(); err != nil {
+		var apiErr *anthropic.Error
+		if errors.As(err, &apiErr) || ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, &streamInterruptedError{err: err}
+	}
+	return &message, nil
+}
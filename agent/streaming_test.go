@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// useShortRetryDelays shrinks the backoff so retry tests run quickly
+func useShortRetryDelays(t *testing.T) {
+	t.Helper()
+	oldMin, oldMax := minRetryDelay, maxRetryDelay
+	minRetryDelay, maxRetryDelay = time.Millisecond, 4*time.Millisecond
+	t.Cleanup(func() { minRetryDelay, maxRetryDelay = oldMin, oldMax })
+}
+
+func TestRetryDelay(t *testing.T) {
+	for attempt := 0; attempt < 40; attempt++ {
+		ceiling := maxRetryDelay
+		if attempt < 5 {
+			ceiling = minRetryDelay << attempt
+		}
+		for i := 0; i < 20; i++ {
+			delay := retryDelay(attempt)
+			if delay < minRetryDelay || delay > ceiling {
+				t.Fatalf("retryDelay(%d) = %s, want between %s and %s", attempt, delay, minRetryDelay, ceiling)
+			}
+		}
+	}
+}
+
+func TestRequestWithRetriesRecoversFromTransientErrors(t *testing.T) {
+	useShortRetryDelays(t)
+	agent, api := newStubAgent(t,
+		stubResponse{status: http.StatusTooManyRequests, body: `{"type":"error","error":{"type":"rate_limit_error","message":"rate limited"}}`},
+		stubResponse{status: statusOverloaded, body: `{"type":"error","error":{"type":"overloaded_error","message":"overloaded"}}`},
+		stubResponse{text: "done"},
+	)
+
+	message, _, err := agent.requestWithRetries(context.Background(), conversationWithToolResult("output"))
+	if err != nil {
+		t.Fatalf("requestWithRetries() error = %v", err)
+	}
+	if len(message.Content) != 1 || message.Content[0].Text != "done" {
+		t.Errorf("message content = %+v, want the text \"done\"", message.Content)
+	}
+	if got := api.requestCount(); got != 3 {
+		t.Errorf("requests = %d, want 3", got)
+	}
+}
+
+func TestRequestWithRetriesStopsAtBudget(t *testing.T) {
+	useShortRetryDelays(t)
+	agent, api := newStubAgent(t,
+		stubResponse{status: http.StatusServiceUnavailable, body: `{"type":"error","error":{"type":"api_error","message":"unavailable"}}`},
+		stubResponse{text: "done"},
+	)
+	agent.retryBudget = 0
+
+	if _, _, err := agent.requestWithRetries(context.Background(), conversationWithToolResult("output")); err == nil {
+		t.Fatal("requestWithRetries() succeeded, want the error once the budget is spent")
+	}
+	if got := api.requestCount(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestRequestWithRetriesByErrorClass(t *testing.T) {
+	useShortRetryDelays(t)
+	tests := []struct {
+		status     int
+		retryable  bool
+		wantStatus string
+	}{
+		{http.StatusBadRequest, false, "400 Bad Request"},
+		{http.StatusUnauthorized, false, "401 Unauthorized, check the API key and its permissions"},
+		{http.StatusForbidden, false, "403 Forbidden, check the API key and its permissions"},
+		{http.StatusNotFound, false, "404 Not Found"},
+		{http.StatusTooManyRequests, true, "429 rate limited"},
+		{http.StatusInternalServerError, true, "500 Internal Server Error"},
+		{http.StatusBadGateway, true, "502 Bad Gateway"},
+		{http.StatusServiceUnavailable, true, "503 Service Unavailable"},
+		{statusOverloaded, true, "529 overloaded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			agent, api := newStubAgent(t,
+				stubResponse{status: tt.status, body: `{"type":"error","error":{"type":"error","message":"failed"}}`},
+				stubResponse{text: "done"},
+			)
+
+			// Inspect the error a single attempt produces
+			_, err := agent.runInference(context.Background(), conversationWithToolResult("output"))
+			if err == nil {
+				t.Fatal("runInference() succeeded, want an error")
+			}
+			if got := isRetryableError(err); got != tt.retryable {
+				t.Errorf("isRetryableError() = %v, want %v", got, tt.retryable)
+			}
+			if got := describeAPIStatus(err); got != tt.wantStatus {
+				t.Errorf("describeAPIStatus() = %q, want %q", got, tt.wantStatus)
+			}
+
+			// Retryable errors go on to the next response, fatal ones stop immediately
+			api.responses = []stubResponse{{status: tt.status, body: `{"type":"error","error":{"type":"error","message":"failed"}}`}, {text: "done"}}
+			api.requests = nil
+			_, _, err = agent.requestWithRetries(context.Background(), conversationWithToolResult("output"))
+			if tt.retryable && (err != nil || api.requestCount() != 2) {
+				t.Errorf("requestWithRetries() error = %v after %d requests, want success after 2", err, api.requestCount())
+			}
+			if !tt.retryable && (err == nil || api.requestCount() != 1) {
+				t.Errorf("requestWithRetries() error = %v after %d requests, want a failure after 1", err, api.requestCount())
+			}
+		})
+	}
+}
+
+func TestIsRetryableErrorInterruptedStream(t *testing.T) {
+	if !isRetryableError(&streamInterruptedError{err: context.DeadlineExceeded}) {
+		t.Error("isRetryableError() = false for an interrupted stream, want true")
+	}
+	if isRetryableError(context.Canceled) {
+		t.Error("isRetryableError() = true for a plain error, want false")
+	}
+	if got := describeAPIStatus(context.Canceled); got != "" {
+		t.Errorf("describeAPIStatus() = %q for a non-API error, want \"\"", got)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// OutlineSymbol represents a top-level declaration found in a file
+type OutlineSymbol struct {
+	Name string // Name of the declared symbol
+	Kind string // Kind of declaration (e.g. func, type, class)
+	Line int    // Line number of the declaration (1-based)
+	End  int    // Last line of the declaration's scope (1-based)
+}
+
+// Patterns for top-level declarations in common languages. Each must capture the kind and then the name.
+var topLevelDeclarationPatterns = []*regexp.Regexp{
+	// Go methods: func (r *Receiver) Name(
+	regexp.MustCompile(`^(func)\s+\([^)]*\)\s*([A-Za-z_]\w*)`),
+	// Go, Rust, Swift, Kotlin functions and types
+	regexp.MustCompile(`^(?:pub(?:\([^)]*\))?\s+)?(?:async\s+)?(func|fn|type|struct|enum|trait|impl|interface|fun)\s+(?:<[^>]*>\s*)?([A-Za-z_]\w*)`),
+	// JavaScript / TypeScript
+	regexp.MustCompile(`^(?:export\s+)?(?:default\s+)?(?:async\s+)?(function\*?|class|interface|type|enum)\s+([A-Za-z_$][\w$]*)`),
+	regexp.MustCompile(`^(?:export\s+)?(const|let|var)\s+([A-Za-z_$][\w$]*)\s*=\s*(?:async\s+)?(?:\([^)]*\)|[A-Za-z_$][\w$]*)\s*=>`),
+	// Python and Ruby
+	regexp.MustCompile(`^(?:async\s+)?(def|class|module)\s+([A-Za-z_]\w*)`),
+	// Java, C#, and similar class-based languages
+	regexp.MustCompile(`^(?:(?:public|private|protected|internal|static|final|abstract|sealed|partial)\s+)*(class|interface|enum|record)\s+([A-Za-z_]\w*)`),
+}
+
+// ExtractTopLevelSymbols finds top-level declarations in file content.
+// Only unindented lines are considered, which keeps the heuristic language-agnostic.
+func ExtractTopLevelSymbols(content string) []OutlineSymbol {
+	var symbols []OutlineSymbol
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if line == "" || line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+
+		for _, pattern := range topLevelDeclarationPatterns {
+			if match := pattern.FindStringSubmatch(line); match != nil {
+				symbols = append(symbols, OutlineSymbol{Kind: match[1], Name: match[2], Line: i + 1, End: scopeEnd(lines, i)})
+				break
+			}
+		}
+	}
+	return symbols
+}
+
+// scopeEnd returns the last line (1-based) of the declaration on line index start: its body is
+// the indented lines that follow, plus an unindented closing brace or "end". Conflict markers
+// are skipped so a chunk inside a body does not close it.
+func scopeEnd(lines []string, start int) int {
+	end := start + 1
+	for i := start + 1; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r")
+		switch {
+		case line == "":
+			continue
+		case line[0] == ' ' || line[0] == '\t' || isConflictMarker(line) || line[0] == ')':
+			// Body lines, and the rest of a signature split over several lines
+			end = i + 1
+		case line[0] == '}' || line == "end":
+			return i + 1
+		default:
+			return end
+		}
+	}
+	return end
+}
+
+// SymbolsForRange returns the symbol enclosing startLine followed by any symbols declared inside the range
+func SymbolsForRange(symbols []OutlineSymbol, startLine, endLine int) []OutlineSymbol {
+	var result []OutlineSymbol
+	var enclosing *OutlineSymbol
+	for i := range symbols {
+		if symbols[i].Line < startLine {
+			// A symbol whose scope closed before the range does not enclose it
+			enclosing = nil
+			if symbols[i].End >= startLine {
+				enclosing = &symbols[i]
+			}
+		} else if symbols[i].Line <= endLine {
+			result = append(result, symbols[i])
+		}
+	}
+
+	if enclosing != nil {
+		result = append([]OutlineSymbol{*enclosing}, result...)
+	}
+	return result
+}
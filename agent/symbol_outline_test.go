@@ -0,0 +1,72 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSymbolsForRange(t *testing.T) {
+	content := `package main
+
+import "fmt"
+
+func parseConfig(path string) error {
+<<<<<<< HEAD
+	fmt.Println("ours")
+=======
+	fmt.Println("theirs")
+>>>>>>> feature
+	return nil
+}
+
+<<<<<<< HEAD
+const limit = 10
+=======
+const limit = 20
+>>>>>>> feature
+
+func split(
+	a int,
+) int {
+	return a
+}
+`
+	symbols := ExtractTopLevelSymbols(content)
+	wantSymbols := []OutlineSymbol{
+		{Name: "parseConfig", Kind: "func", Line: 5, End: 12},
+		{Name: "split", Kind: "func", Line: 20, End: 24},
+	}
+	if !reflect.DeepEqual(symbols, wantSymbols) {
+		t.Fatalf("ExtractTopLevelSymbols() = %+v, want %+v", symbols, wantSymbols)
+	}
+
+	tests := []struct {
+		name       string
+		start, end int
+		want       string
+	}{
+		{"inside a function", 6, 10, "func parseConfig"},
+		{"between declarations", 14, 18, "top level"},
+		{"spanning two functions", 11, 21, "spans func parseConfig, func split"},
+		{"after a multi-line signature", 23, 23, "func split"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunk := ConflictChunk{StartLine: tt.start, EndLine: tt.end}
+			if got := formatChunkScope(symbols, chunk); got != tt.want {
+				t.Errorf("formatChunkScope(lines %d-%d) = %q, want %q", tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopeEndIndentedBody(t *testing.T) {
+	content := "def first():\n    return 1\n\nx = 2\n\nclass Second:\n    pass\n"
+	symbols := ExtractTopLevelSymbols(content)
+	if len(symbols) != 2 || symbols[0].End != 2 || symbols[1].End != 7 {
+		t.Errorf("ExtractTopLevelSymbols() = %+v, want first ending on line 2 and Second on line 7", symbols)
+	}
+	if got := SymbolsForRange(symbols, 4, 4); len(got) != 0 {
+		t.Errorf("SymbolsForRange(line 4) = %+v, want no enclosing symbol", got)
+	}
+}
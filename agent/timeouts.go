@@ -0,0 +1,19 @@
+package main
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// Default time a single tool call may run before it is abandoned
+const defaultToolTimeout = 2 * time.Minute
+
+// toolCommand prepares a command that is killed once ctx, the context of the tool call running
+// it, is cancelled
+func toolCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	// Don't wait forever on output pipes held open by grandchildren of a killed shell
+	cmd.WaitDelay = 5 * time.Second
+	return cmd
+}
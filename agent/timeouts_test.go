@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCallToolWithTimeout(t *testing.T) {
+	agent, _ := newStubAgent(t)
+	agent.toolTimeout = 20 * time.Millisecond
+
+	// slowTool finishes well after the timeout, recording that it ran to completion
+	slowTool := func(name string, finished *atomic.Bool) ToolDefinition {
+		return ToolDefinition{Name: name, Function: func(context.Context, json.RawMessage) (string, error) {
+			time.Sleep(100 * time.Millisecond)
+			finished.Store(true)
+			return "written", nil
+		}}
+	}
+
+	var readFinished atomic.Bool
+	_, err := agent.callToolWithTimeout(context.Background(), slowTool("view_file", &readFinished), json.RawMessage(`{}`))
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("read-only tool: error = %v, want a timeout", err)
+	}
+	if readFinished.Load() {
+		t.Error("read-only tool: the call waited for the tool to finish, want it abandoned")
+	}
+
+	var writeFinished atomic.Bool
+	response, err := agent.callToolWithTimeout(context.Background(), slowTool("edit_file_line", &writeFinished), json.RawMessage(`{}`))
+	if !writeFinished.Load() {
+		t.Error("mutating tool: the call returned while the tool was still running")
+	}
+	if err != nil || response != "written" {
+		t.Errorf("mutating tool: = %q, %v, want the tool's own result", response, err)
+	}
+}
+
+func TestAbandonedToolCommandsAreCancelled(t *testing.T) {
+	agent, _ := newStubAgent(t)
+	agent.toolTimeout = 20 * time.Millisecond
+
+	// The tool starts a long command only after the call was abandoned; it must run under the
+	// abandoned call's cancelled context, not an unbounded one
+	commandErr := make(chan error, 1)
+	tool := ToolDefinition{Name: "view_file", Function: func(ctx context.Context, _ json.RawMessage) (string, error) {
+		time.Sleep(100 * time.Millisecond)
+		commandErr <- toolCommand(ctx, "sleep", "5").Run()
+		return "", nil
+	}}
+
+	if _, err := agent.callToolWithTimeout(context.Background(), tool, json.RawMessage(`{}`)); err == nil {
+		t.Fatal("callToolWithTimeout() succeeded, want a timeout")
+	}
+	// A second call installs a fresh context while the abandoned tool is still running
+	quick := ToolDefinition{Name: "view_file", Function: func(context.Context, json.RawMessage) (string, error) {
+		return "ok", nil
+	}}
+	if _, err := agent.callToolWithTimeout(context.Background(), quick, json.RawMessage(`{}`)); err != nil {
+		t.Fatalf("callToolWithTimeout() error = %v", err)
+	}
+
+	select {
+	case err := <-commandErr:
+		if err == nil {
+			t.Error("command started after the call was abandoned ran to completion, want it cancelled")
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("command started after the call was abandoned is still running, want it cancelled")
+	}
+}
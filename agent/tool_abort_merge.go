@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+var AbortMergeDefinition = ToolDefinition{
+	Name:        "abort_merge",
+	Description: "Abort the merge in progress with 'git merge --abort', discarding every resolution made so far and returning the repository to its state before the merge. This is a LAST RESORT for when the working tree is beyond repair; prefer restore_file to undo edits to a single file.",
+	InputSchema: AbortMergeInputSchema,
+	Function:    AbortMerge,
+}
+
+type AbortMergeInput struct {
+	// No parameters needed for this tool
+}
+
+var AbortMergeInputSchema = GenerateSchema[AbortMergeInput]()
+
+func AbortMerge(ctx context.Context, input json.RawMessage) (string, error) {
+	if !IsMergeInProgress(ctx) {
+		return "No merge is in progress (MERGE_HEAD does not exist), so there is nothing to abort", nil
+	}
+
+	// Remember what was being merged so the merge can be started again
+	mergeHead, _ := ExecuteGitCommand(ctx, "rev-parse", "--short", "MERGE_HEAD")
+
+	if _, err := ExecuteGitCommand(ctx, "merge", "--abort"); err != nil {
+		return "", fmt.Errorf("failed to abort the merge: %w", err)
+	}
+
+	// The working tree no longer has the edited files' conflicted versions to restore
+	clearSnapshots()
+
+	result := "Aborted the merge; the repository is back to its state before the merge started."
+	if mergeHead != "" {
+		result += fmt.Sprintf(" The merge can be started again with 'git merge %s'.", mergeHead)
+	}
+	return result, nil
+}
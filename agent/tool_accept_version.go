@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var AcceptVersionDefinition = ToolDefinition{
+	Name:        "accept_version",
+	Description: "Resolve a whole conflicted file by taking one side's version with 'git checkout --ours' or '--theirs', then stage it. Use this when one side should win the entire file, instead of resolving it chunk by chunk. Changes from the other side to this file are discarded.",
+	InputSchema: AcceptVersionInputSchema,
+	Function:    AcceptVersion,
+}
+
+type AcceptVersionInput struct {
+	Path string `json:"path" jsonschema_description:"The path to the conflicted file"`
+	Side string `json:"side" jsonschema_description:"The version to keep: 'ours' (the current branch) or 'theirs' (the branch being merged in)"`
+}
+
+var AcceptVersionInputSchema = GenerateSchema[AcceptVersionInput]()
+
+func AcceptVersion(ctx context.Context, input json.RawMessage) (string, error) {
+	var params AcceptVersionInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if params.Side != "ours" && params.Side != "theirs" {
+		return "", fmt.Errorf("invalid side %q: must be ours or theirs", params.Side)
+	}
+	if err := checkProtected(params.Path); err != nil {
+		return "", err
+	}
+
+	// Only files git still considers conflicted have both versions to choose from
+	unmerged, err := ListUnmergedFiles(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list unmerged files: %w", err)
+	}
+	conflicted := false
+	for _, file := range unmerged {
+		if filepath.Clean(file) == filepath.Clean(params.Path) {
+			conflicted = true
+			break
+		}
+	}
+	if !conflicted {
+		return "", fmt.Errorf("%s is not in a conflicted state; see_git_status lists the conflicted files", params.Path)
+	}
+
+	// Name the side that was taken using the branch label on the conflict markers
+	label := "HEAD"
+	if params.Side == "theirs" {
+		label = "the incoming branch"
+	}
+	if content, err := os.ReadFile(params.Path); err == nil {
+		if chunks, err := FindConflictChunks(string(content)); err == nil && len(chunks) > 0 {
+			label = chunks[0].BaseLabel
+			if params.Side == "theirs" {
+				label = chunks[0].IncomingLabel
+			}
+		}
+	}
+
+	snapshotFile(params.Path)
+	if _, err := ExecuteGitCommand(ctx, "checkout", "--"+params.Side, "--", params.Path); err != nil {
+		return "", fmt.Errorf("failed to take %s version of %s (if that side deleted the file, use delete_file instead): %w", params.Side, params.Path, err)
+	}
+	if _, err := ExecuteGitCommand(ctx, "add", "--", params.Path); err != nil {
+		return "", fmt.Errorf("failed to stage %s: %w", params.Path, err)
+	}
+
+	return fmt.Sprintf("Took the %s version (%s) of %s and staged it; the other side's changes to this file were discarded.",
+		params.Side, label, params.Path) + recordEdit(params.Path), nil
+}
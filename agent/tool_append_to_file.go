@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var AppendToFileDefinition = ToolDefinition{
+	Name:        "append_to_file",
+	Description: "Add content to the end of a file without rewriting it, e.g. to add an entry to a list at the end of a file. A newline is inserted first if the file does not end with one. Returns the new line count.",
+	InputSchema: AddToFileInputSchema,
+	Function:    AppendToFile,
+}
+
+var PrependToFileDefinition = ToolDefinition{
+	Name:        "prepend_to_file",
+	Description: "Add content to the start of a file without rewriting it, e.g. a license header. Existing lines move down. Returns the new line count.",
+	InputSchema: AddToFileInputSchema,
+	Function:    PrependToFile,
+}
+
+type AddToFileInput struct {
+	Path    string `json:"path" jsonschema_description:"The path to the file to add to"`
+	Content string `json:"content" jsonschema_description:"The lines to add (use \n for line breaks). A trailing newline is added if missing."`
+
+	AllowOutsideConflicts bool `json:"allow_outside_conflicts,omitempty" jsonschema_description:"Allow the addition when strict region mode is enabled and it falls outside the original conflict regions. Only use this when the edit is required by the resolution."`
+}
+
+var AddToFileInputSchema = GenerateSchema[AddToFileInput]()
+
+func AppendToFile(ctx context.Context, input json.RawMessage) (string, error) {
+	return addToFile(ctx, input, false)
+}
+
+func PrependToFile(ctx context.Context, input json.RawMessage) (string, error) {
+	return addToFile(ctx, input, true)
+}
+
+// addToFile adds content to the start or end of a file, keeping the file's line endings
+func addToFile(ctx context.Context, input json.RawMessage, prepend bool) (string, error) {
+	var params AddToFileInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if err := ValidateFileExists(params.Path); err != nil {
+		return "", err
+	}
+	if params.Content == "" {
+		return "", fmt.Errorf("content cannot be empty")
+	}
+
+	content, err := os.ReadFile(params.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	text, eol := toLF(string(content))
+	oldLineCount := countLines(text)
+
+	// Refuse additions away from the conflicts in strict region mode
+	if !params.AllowOutsideConflicts {
+		line := oldLineCount + 1
+		if prepend {
+			line = 1
+		}
+		if err := checkConflictRegion(params.Path, line, line); err != nil {
+			return "", err
+		}
+	}
+
+	// Warn about (or refuse) editing files git does not track
+	warning, err := checkTrackedForEdit(ctx, params.Path)
+	if err != nil {
+		return "", err
+	}
+
+	addition, _ := toLF(params.Content)
+	if !strings.HasSuffix(addition, "\n") {
+		addition += "\n"
+	}
+
+	var result string
+	if prepend {
+		result = addition + text
+	} else {
+		if text != "" && !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+		result = text + addition
+	}
+
+	snapshotFile(params.Path)
+	if err := os.WriteFile(params.Path, []byte(fromLF(result, eol)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write updated content to file: %w", err)
+	}
+
+	addedLines := strings.Count(addition, "\n")
+	if prepend {
+		shiftConflictRegions(params.Path, 1, 0, addedLines)
+	}
+
+	warning += placeholderWarning(params.Path, "", params.Content)
+	warning += recordEdit(params.Path)
+
+	where := "end"
+	if prepend {
+		where = "start"
+	}
+	return fmt.Sprintf("Added %d line(s) to the %s of %s, which now has %d lines%s",
+		addedLines, where, params.Path, countLines(result), warning), nil
+}
+
+// countLines returns the number of lines in LF-normalized text, not counting an empty line after a final newline
+func countLines(text string) int {
+	if text == "" {
+		return 0
+	}
+	return strings.Count(strings.TrimSuffix(text, "\n"), "\n") + 1
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var CheckBalanceDefinition = ToolDefinition{
+	Name:        "check_balance",
+	Description: "Check that braces, brackets, and parentheses are balanced across a whole file, ignoring strings and comments. For Python files, also checks that indentation is consistent. Reports the first imbalance found. Run it during cleanup to catch structural breakage from a bad merge (e.g. a dropped closing brace). Supports Go, JavaScript/TypeScript, and Python files only.",
+	InputSchema: CheckBalanceInputSchema,
+	Function:    CheckBalance,
+}
+
+type CheckBalanceInput struct {
+	Path string `json:"path" jsonschema_description:"The path to the file to check"`
+}
+
+var CheckBalanceInputSchema = GenerateSchema[CheckBalanceInput]()
+
+func CheckBalance(ctx context.Context, input json.RawMessage) (string, error) {
+	var params CheckBalanceInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if err := ValidateFileExists(params.Path); err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(params.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	syntax, ok := syntaxForPath(params.Path)
+	if !ok {
+		return "", fmt.Errorf("unsupported file type %q: check_balance supports Go, JavaScript/TypeScript, and Python files", filepath.Ext(params.Path))
+	}
+	if issue := FindImbalance(string(content), syntax); issue != "" {
+		return fmt.Sprintf("%s is not balanced: %s", params.Path, issue), nil
+	}
+	return fmt.Sprintf("%s is balanced (checked as %s)", params.Path, syntax.name), nil
+}
+
+// bracketSyntax describes the comment and string syntax of a language family
+type bracketSyntax struct {
+	name          string
+	lineComment   string
+	blockComments bool   // Supports /* */ comments
+	quotes        string // Characters that start single-line strings
+	rawQuote      byte   // Character that starts a multi-line raw or template string, or 0
+	tripleQuotes  bool   // Supports Python ''' and """ strings
+	indentBlocks  bool   // Blocks are delimited by indentation
+}
+
+var (
+	goSyntax     = bracketSyntax{name: "Go", lineComment: "//", blockComments: true, quotes: `"'`, rawQuote: '`'}
+	jsSyntax     = bracketSyntax{name: "JavaScript/TypeScript", lineComment: "//", blockComments: true, quotes: `"'`, rawQuote: '`'}
+	pythonSyntax = bracketSyntax{name: "Python", lineComment: "#", quotes: `"'`, tripleQuotes: true, indentBlocks: true}
+)
+
+// syntaxForPath picks the bracket syntax for a file based on its extension, reporting false
+// for languages whose comment and string rules are not known
+func syntaxForPath(path string) (bracketSyntax, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".go":
+		return goSyntax, true
+	case ".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx":
+		return jsSyntax, true
+	case ".py", ".pyi":
+		return pythonSyntax, true
+	default:
+		return bracketSyntax{}, false
+	}
+}
+
+// openBracket is a bracket waiting to be closed
+type openBracket struct {
+	char      byte
+	line, col int
+}
+
+var closingBrackets = map[byte]byte{')': '(', ']': '[', '}': '{'}
+
+// FindImbalance scans content for the first unbalanced bracket (or, for indentation-based
+// languages, inconsistent indentation) and describes it, or returns an empty string if there is none
+func FindImbalance(content string, syntax bracketSyntax) string {
+	var stack []openBracket
+	line, col := 1, 0
+
+	// Indentation tracking for indentation-based languages
+	indents := []int{0}
+	atLineStart := true
+	var last byte // Last significant character outside comments, or a quote after a string
+
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		col++
+
+		if c == '\n' {
+			line++
+			col = 0
+			atLineStart = true
+			continue
+		}
+
+		if syntax.indentBlocks && atLineStart {
+			atLineStart = false
+			// Bracketed expressions and backslash continuations may be indented freely
+			if len(stack) == 0 && last != '\\' {
+				opensBlock := last == ':'
+				if issue := checkIndent(content, i, line, indents, opensBlock); issue != "" {
+					return issue
+				}
+				indents = updateIndents(content, i, indents, opensBlock)
+			}
+		}
+		if c != ' ' && c != '\t' && c != '\r' && !strings.HasPrefix(content[i:], syntax.lineComment) {
+			last = c
+		}
+
+		rest := content[i:]
+		switch {
+		case strings.HasPrefix(rest, syntax.lineComment):
+			for i+1 < len(content) && content[i+1] != '\n' {
+				i++
+			}
+			continue
+		case syntax.blockComments && strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				return fmt.Sprintf("unclosed block comment starting at line %d, column %d", line, col)
+			}
+			line, col = advance(rest[:end+4], line, col)
+			i += end + 3
+			continue
+		case syntax.tripleQuotes && (strings.HasPrefix(rest, `"""`) || strings.HasPrefix(rest, "'''")):
+			end := strings.Index(rest[3:], rest[:3])
+			if end < 0 {
+				return fmt.Sprintf("unclosed triple-quoted string starting at line %d, column %d", line, col)
+			}
+			line, col = advance(rest[:end+6], line, col)
+			i += end + 5
+			continue
+		case syntax.rawQuote != 0 && c == syntax.rawQuote:
+			end := strings.IndexByte(rest[1:], c)
+			if end < 0 {
+				return fmt.Sprintf("unclosed string starting at line %d, column %d", line, col)
+			}
+			line, col = advance(rest[:end+2], line, col)
+			i += end + 1
+			continue
+		case strings.IndexByte(syntax.quotes, c) >= 0:
+			startCol := col
+			for i+1 < len(content) && content[i+1] != c && content[i+1] != '\n' {
+				i++
+				col++
+				if content[i] == '\\' && i+1 < len(content) && content[i+1] != '\n' {
+					i++
+					col++
+				}
+			}
+			if i+1 >= len(content) || content[i+1] == '\n' {
+				return fmt.Sprintf("unclosed string starting at line %d, column %d", line, startCol)
+			}
+			i++
+			col++
+			continue
+		}
+
+		switch c {
+		case '(', '[', '{':
+			stack = append(stack, openBracket{char: c, line: line, col: col})
+		case ')', ']', '}':
+			if len(stack) == 0 {
+				return fmt.Sprintf("unexpected '%c' at line %d, column %d with nothing to close", c, line, col)
+			}
+			top := stack[len(stack)-1]
+			if top.char != closingBrackets[c] {
+				return fmt.Sprintf("'%c' at line %d, column %d does not match '%c' opened at line %d, column %d",
+					c, line, col, top.char, top.line, top.col)
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	if len(stack) > 0 {
+		top := stack[len(stack)-1]
+		return fmt.Sprintf("'%c' opened at line %d, column %d is never closed", top.char, top.line, top.col)
+	}
+	return ""
+}
+
+// advance moves a line/column position past text
+func advance(text string, line, col int) (int, int) {
+	newlines := strings.Count(text, "\n")
+	if newlines == 0 {
+		return line, col + len(text) - 1
+	}
+	return line + newlines, len(text) - strings.LastIndexByte(text, '\n') - 1
+}
+
+// lineIndent returns the indentation width of the line starting at offset, or -1 for blank
+// and comment-only lines, which do not affect Python's block structure
+func lineIndent(content string, offset int) int {
+	end := strings.IndexByte(content[offset:], '\n')
+	if end < 0 {
+		end = len(content) - offset
+	}
+	text := strings.TrimRight(content[offset:offset+end], "\r")
+	trimmed := strings.TrimLeft(text, " \t")
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return -1
+	}
+	return len(strings.ReplaceAll(text[:len(text)-len(trimmed)], "\t", "        "))
+}
+
+// checkIndent reports an indented line that does not follow a block opener, or a dedent
+// to a level that was never opened
+func checkIndent(content string, offset, line int, indents []int, opensBlock bool) string {
+	indent := lineIndent(content, offset)
+	if indent < 0 {
+		return ""
+	}
+
+	current := indents[len(indents)-1]
+	switch {
+	case indent > current && !opensBlock:
+		return fmt.Sprintf("unexpected indent at line %d", line)
+	case indent <= current && opensBlock:
+		return fmt.Sprintf("expected an indented block at line %d", line)
+	case indent < current:
+		for _, level := range indents {
+			if level == indent {
+				return ""
+			}
+		}
+		return fmt.Sprintf("dedent at line %d does not match any outer indentation level", line)
+	}
+	return ""
+}
+
+// updateIndents pushes or pops indentation levels for the line starting at offset
+func updateIndents(content string, offset int, indents []int, opensBlock bool) []int {
+	indent := lineIndent(content, offset)
+	if indent < 0 {
+		return indents
+	}
+
+	if opensBlock && indent > indents[len(indents)-1] {
+		return append(indents, indent)
+	}
+	for len(indents) > 1 && indents[len(indents)-1] > indent {
+		indents = indents[:len(indents)-1]
+	}
+	return indents
+}
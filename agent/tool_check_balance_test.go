@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCheckBalance(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+		wantErr string
+	}{
+		{"balanced Go", "main.go", "func f() {\n\ts := \"}\"\n}\n", "is balanced (checked as Go)", ""},
+		{"unclosed Go brace", "main.go", "func f() {\n\tif x {\n}\n", "is not balanced", ""},
+		{"balanced TypeScript", "app.ts", "const f = () => { return `${a}` }\n", "is balanced (checked as JavaScript/TypeScript)", ""},
+		{"balanced Python", "app.py", "def f():\n    return (1,\n  2)\n", "is balanced (checked as Python)", ""},
+		{"Ruby is unsupported", "app.rb", "def f\n  x = '}'\nend\n", "", "unsupported file type \".rb\""},
+		{"shell is unsupported", "run.sh", "echo '('\n", "", "unsupported file type \".sh\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTempFile(t, tt.file, tt.content)
+			input, _ := json.Marshal(CheckBalanceInput{Path: path})
+			got, err := CheckBalance(context.Background(), input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CheckBalance() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckBalance() error = %v", err)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("CheckBalance() = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Matches unqualified calls like name( that are not method or package calls
+var callPattern = regexp.MustCompile(`(^|[^\w.$])([A-Za-z_$][\w$]*)\s*\(`)
+
+// Names that look like calls but are keywords or builtins, per language
+var builtinCallNames = map[string]map[string]bool{
+	"go": setOf("if", "for", "switch", "func", "return", "go", "defer", "select", "case", "range", "map", "chan", "struct", "interface",
+		"append", "cap", "clear", "close", "complex", "copy", "delete", "imag", "len", "make", "max", "min", "new", "panic",
+		"print", "println", "real", "recover", "bool", "byte", "rune", "string", "error", "any", "int", "int8", "int16", "int32",
+		"int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "float32", "float64", "complex64", "complex128"),
+	"js": setOf("if", "for", "while", "switch", "catch", "function", "return", "typeof", "new", "await", "super", "import",
+		"require", "constructor", "parseInt", "parseFloat", "isNaN", "isFinite", "setTimeout", "setInterval", "clearTimeout",
+		"clearInterval", "fetch", "Number", "String", "Boolean", "Array", "Object", "Symbol", "BigInt", "Date", "Error",
+		"Promise", "RegExp", "Map", "Set", "encodeURIComponent", "decodeURIComponent", "structuredClone", "queueMicrotask"),
+	"py": setOf("if", "elif", "while", "for", "return", "not", "and", "or", "in", "lambda", "assert", "print", "len", "range",
+		"str", "int", "float", "bool", "list", "dict", "set", "tuple", "type", "isinstance", "issubclass", "super", "open",
+		"enumerate", "zip", "map", "filter", "sorted", "reversed", "sum", "min", "max", "abs", "any", "all", "repr", "hash",
+		"getattr", "setattr", "hasattr", "iter", "next", "round", "format", "vars", "dir", "id", "input", "bytes", "frozenset",
+		"object", "property", "staticmethod", "classmethod", "Exception", "ValueError", "TypeError", "KeyError", "RuntimeError"),
+}
+
+var CheckReferencesDefinition = ToolDefinition{
+	Name:        "check_references",
+	Description: "Check that functions called on lines the resolution changed (relative to HEAD) are still defined somewhere in the repository, flagging calls to functions that one side removed or renamed. Heuristic: only unqualified calls in Go, JavaScript/TypeScript, and Python files are checked.",
+	InputSchema: CheckReferencesInputSchema,
+	Function:    CheckReferences,
+}
+
+type CheckReferencesInput struct {
+	Path string `json:"path" jsonschema_description:"The path to the resolved file to check"`
+}
+
+var CheckReferencesInputSchema = GenerateSchema[CheckReferencesInput]()
+
+func CheckReferences(ctx context.Context, input json.RawMessage) (string, error) {
+	var params CheckReferencesInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if err := ValidateFileExists(params.Path); err != nil {
+		return "", err
+	}
+
+	ext := strings.ToLower(filepath.Ext(params.Path))
+	language := referenceLanguage(ext)
+	if language == "" {
+		return fmt.Sprintf("Reference checking is not supported for %s files", ext), nil
+	}
+
+	content, err := os.ReadFile(params.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	changed, err := ChangedLines(ctx, params.Path)
+	if err != nil {
+		return "", err
+	}
+
+	// Collect calls on changed lines
+	lines := strings.Split(string(content), "\n")
+	callLines := make(map[string][]int)
+	for lineNum := range changed {
+		if lineNum < 1 || lineNum > len(lines) {
+			continue
+		}
+		for _, match := range callPattern.FindAllStringSubmatch(lines[lineNum-1], -1) {
+			name := match[2]
+			if !builtinCallNames[language][name] {
+				callLines[name] = append(callLines[name], lineNum)
+			}
+		}
+	}
+	if len(callLines) == 0 {
+		return fmt.Sprintf("No calls found on lines of %s changed by the resolution", params.Path), nil
+	}
+
+	names := make([]string, 0, len(callLines))
+	for name := range callLines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	defined, err := findDefinitions(ctx, names, "*"+ext)
+	if err != nil {
+		return "", err
+	}
+
+	var dangling []string
+	for _, name := range names {
+		// Locals, parameters, and imports show up in the file without being called
+		if defined[name] || usedWithoutCall(string(content), name) {
+			continue
+		}
+		sort.Ints(callLines[name])
+		dangling = append(dangling, fmt.Sprintf("  %s (called on line(s) %s)", name, joinInts(callLines[name])))
+	}
+
+	if len(dangling) == 0 {
+		return fmt.Sprintf("All %d function(s) called on changed lines of %s are defined", len(names), params.Path), nil
+	}
+	return fmt.Sprintf("Found %d call(s) on changed lines of %s to functions with no definition in the repository:\n%s\n\n"+
+		"One side of the merge may have removed or renamed them. Check with search_symbol before keeping these calls.",
+		len(dangling), params.Path, strings.Join(dangling, "\n")), nil
+}
+
+// referenceLanguage maps a file extension to the language key of builtinCallNames
+func referenceLanguage(ext string) string {
+	switch ext {
+	case ".go":
+		return "go"
+	case ".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx":
+		return "js"
+	case ".py":
+		return "py"
+	}
+	return ""
+}
+
+// findDefinitions searches files matching includePattern for declarations of names
+func findDefinitions(ctx context.Context, names []string, includePattern string) (map[string]bool, error) {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+	alternatives := "(" + strings.Join(quoted, "|") + ")"
+
+	// Keyword declarations (func, def, class, ...) and assignments of functions or lambdas
+	declaration := regexp.MustCompile(`\b(?:func|def|function\*?|class|fn|type)\s+(?:\([^)]*\)\s*)?` + alternatives + `\b` +
+		`|\b` + alternatives + `\s*(?::=|=)\s*(?:async\s+)?(?:function|func|lambda|\()`)
+
+	matches, err := grep(ctx, declaration.String(), includePattern, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for definitions: %w", err)
+	}
+
+	defined := make(map[string]bool)
+	for _, match := range matches {
+		for _, submatch := range declaration.FindAllStringSubmatch(match.Content, -1) {
+			defined[submatch[1]] = true
+			defined[submatch[2]] = true
+		}
+	}
+	return defined, nil
+}
+
+// usedWithoutCall reports whether name appears in content other than as a call
+func usedWithoutCall(content, name string) bool {
+	pattern := regexp.MustCompile(`(^|[^\w.$])` + regexp.QuoteMeta(name) + `\b(\s*\()?`)
+	for _, match := range pattern.FindAllStringSubmatch(content, -1) {
+		if match[2] == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// setOf builds a lookup set from its arguments
+func setOf(items ...string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
+
+// joinInts formats ints as a comma-separated list
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = fmt.Sprint(value)
+	}
+	return strings.Join(parts, ", ")
+}
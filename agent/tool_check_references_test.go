@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCheckReferencesFlagsRemovedFunction(t *testing.T) {
+	initTestRepo(t)
+	helpers := "package main\n\nfunc legacyFormat(s string) string {\n\treturn s\n}\n\nfunc newFormat(s string) string {\n\treturn s\n}\n"
+	mergeWithConflicts(t,
+		map[string]string{
+			"helpers.go": helpers,
+			"main.go":    "package main\n\nfunc render(s string) string {\n\treturn s\n}\n",
+		},
+		map[string]string{"main.go": "package main\n\nfunc render(s string) string {\n\treturn legacyFormat(s)\n}\n"},
+		map[string]string{
+			// Their side removes legacyFormat and moves its callers to newFormat
+			"helpers.go": "package main\n\nfunc newFormat(s string) string {\n\treturn s\n}\n",
+			"main.go":    "package main\n\nfunc render(s string) string {\n\treturn newFormat(s)\n}\n",
+		},
+	)
+	// A resolution that keeps calling the removed function
+	writeTree(t, map[string]string{"main.go": "package main\n\nfunc render(s string) string {\n\treturn legacyFormat(s) + newFormat(s)\n}\n"})
+
+	input, _ := json.Marshal(CheckReferencesInput{Path: "main.go"})
+	got, err := CheckReferences(context.Background(), input)
+	if err != nil {
+		t.Fatalf("CheckReferences() error = %v", err)
+	}
+	if !strings.Contains(got, "legacyFormat (called on line(s) 4)") {
+		t.Errorf("CheckReferences() = %q, want legacyFormat flagged", got)
+	}
+	if strings.Contains(got, "newFormat (called") {
+		t.Errorf("CheckReferences() = %q, want newFormat, which is still defined, not flagged", got)
+	}
+}
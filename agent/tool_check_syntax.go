@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var CheckSyntaxDefinition = ToolDefinition{
+	Name:        "check_syntax",
+	Description: "Check that a file parses after resolving it, reporting the location of the first syntax error and any leftover conflict markers. Go, JSON and YAML are parsed directly; JavaScript uses 'node --check' and Python uses python3 when installed. TypeScript, and JavaScript or Python without those interpreters, fall back to the check_balance bracket check; other file types are only checked for conflict markers.",
+	InputSchema: CheckSyntaxInputSchema,
+	Function:    CheckSyntax,
+}
+
+type CheckSyntaxInput struct {
+	Path string `json:"path" jsonschema_description:"The path to the file to check"`
+}
+
+var CheckSyntaxInputSchema = GenerateSchema[CheckSyntaxInput]()
+
+func CheckSyntax(ctx context.Context, input json.RawMessage) (string, error) {
+	var params CheckSyntaxInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if err := ValidateFileExists(params.Path); err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(params.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// Leftover markers are the most common breakage, and confuse the parsers' error locations
+	chunks, err := FindConflictChunks(string(content))
+	if err != nil {
+		return fmt.Sprintf("%s has malformed conflict markers: %v", params.Path, err), nil
+	}
+	if len(chunks) > 0 {
+		return fmt.Sprintf("%s still has %d conflict chunk(s), the first at line %d", params.Path, len(chunks), chunks[0].StartLine), nil
+	}
+
+	language, problem := checkFileSyntax(ctx, params.Path, content)
+	if language == "" {
+		return fmt.Sprintf("%s has no conflict markers; its syntax was not checked because the file type is unsupported", params.Path), nil
+	}
+	if problem != "" {
+		return fmt.Sprintf("%s has a syntax error (checked as %s): %s", params.Path, language, problem), nil
+	}
+	return fmt.Sprintf("%s parses cleanly (checked as %s)", params.Path, language), nil
+}
+
+// checkFileSyntax parses content according to the path's extension and returns the language it
+// was checked as and a description of the first syntax error, or "" if it parses. The language
+// is "" for unsupported file types.
+func checkFileSyntax(ctx context.Context, path string, content []byte) (string, string) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".go":
+		return "Go", goSyntaxError(path, content)
+	case ".json":
+		return "JSON", jsonSyntaxError(content)
+	case ".yml", ".yaml":
+		var value interface{}
+		if err := yaml.Unmarshal(content, &value); err != nil {
+			return "YAML", err.Error()
+		}
+		return "YAML", ""
+	case ".js", ".mjs", ".cjs":
+		if problem, ok := externalSyntaxError(ctx, "node", "--check", path); ok {
+			return "JavaScript (node --check)", problem
+		}
+	case ".py":
+		if problem, ok := externalSyntaxError(ctx, "python3", "-c", pythonSyntaxCheck, path); ok {
+			return "Python", problem
+		}
+	}
+
+	// No parser is available, so at least check the brackets
+	syntax, ok := syntaxForPath(path)
+	if !ok {
+		return "", ""
+	}
+	return syntax.name + " bracket balance", FindImbalance(string(content), syntax)
+}
+
+// Python program that parses the file named by its argument without writing bytecode
+const pythonSyntaxCheck = `import ast, sys
+try:
+    ast.parse(open(sys.argv[1], "rb").read(), sys.argv[1])
+except SyntaxError as e:
+    print(f"line {e.lineno} col {e.offset}: {e.msg}")
+    sys.exit(1)`
+
+// goSyntaxError parses Go source and describes its first few syntax errors
+func goSyntaxError(path string, content []byte) string {
+	_, err := parser.ParseFile(token.NewFileSet(), path, content, parser.AllErrors)
+	if err == nil {
+		return ""
+	}
+
+	var errorList scanner.ErrorList
+	if !errors.As(err, &errorList) {
+		return err.Error()
+	}
+	var problems []string
+	for i, parseErr := range errorList {
+		if i == 3 {
+			problems = append(problems, fmt.Sprintf("and %d more", len(errorList)-i))
+			break
+		}
+		problems = append(problems, fmt.Sprintf("line %d col %d: %s", parseErr.Pos.Line, parseErr.Pos.Column, parseErr.Msg))
+	}
+	return strings.Join(problems, "; ")
+}
+
+// jsonSyntaxError describes where JSON content fails to parse
+func jsonSyntaxError(content []byte) string {
+	var value interface{}
+	err := json.Unmarshal(content, &value)
+	if err == nil {
+		return ""
+	}
+
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return err.Error()
+	}
+	before := content[:syntaxErr.Offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := len(before) - bytes.LastIndexByte(before, '\n')
+	return fmt.Sprintf("line %d col %d: %s", line, col, syntaxErr.Error())
+}
+
+// externalSyntaxError runs a syntax checker and returns its output if it reports a problem.
+// ok is false when the checker is not installed.
+func externalSyntaxError(ctx context.Context, name string, args ...string) (problem string, ok bool) {
+	cmd := toolCommand(ctx, name, args...)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", false
+	}
+	if err != nil {
+		// Keep the message and source excerpt, dropping blank lines and the checker's own stack trace
+		var lines []string
+		for _, line := range strings.Split(output.String(), "\n") {
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" || strings.HasPrefix(trimmed, "at ") || strings.HasPrefix(trimmed, "Node.js ") {
+				continue
+			}
+			lines = append(lines, line)
+		}
+		problem = strings.Join(lines, "\n")
+		if problem == "" {
+			problem = err.Error()
+		}
+		return problem, true
+	}
+	return "", true
+}
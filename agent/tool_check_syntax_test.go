@@ -0,0 +1,20 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCheckSyntaxUnsupportedFileType(t *testing.T) {
+	path := writeTempFile(t, "app.rb", "def f\n  x = '}'\nend\n")
+	input, _ := json.Marshal(CheckSyntaxInput{Path: path})
+	got, err := CheckSyntax(context.Background(), input)
+	if err != nil {
+		t.Fatalf("CheckSyntax() error = %v", err)
+	}
+	if !strings.Contains(got, "file type is unsupported") {
+		t.Errorf("CheckSyntax() = %q, want it to report the unsupported file type", got)
+	}
+}
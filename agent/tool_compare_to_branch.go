@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+var CompareToBranchDefinition = ToolDefinition{
+	Name:        "compare_to_branch",
+	Description: "Show a unified diff between a file's version on a reference branch (e.g. the branch a PR targets) and its current contents in the working tree. Useful for checking that a resolution converges toward where that branch is headed.",
+	InputSchema: CompareToBranchInputSchema,
+	Function:    CompareToBranch,
+}
+
+type CompareToBranchInput struct {
+	Path   string `json:"path" jsonschema_description:"The path to the resolved file to compare"`
+	Branch string `json:"branch" jsonschema_description:"The reference branch (or any commit-ish) to compare against, e.g. 'main'"`
+}
+
+var CompareToBranchInputSchema = GenerateSchema[CompareToBranchInput]()
+
+func CompareToBranch(ctx context.Context, input json.RawMessage) (string, error) {
+	var params CompareToBranchInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if err := ValidateFileExists(params.Path); err != nil {
+		return "", err
+	}
+	if params.Branch == "" {
+		return "", fmt.Errorf("branch cannot be empty")
+	}
+
+	if _, err := ExecuteGitCommand(ctx, "rev-parse", "--verify", "--quiet", params.Branch+"^{commit}"); err != nil {
+		return "", fmt.Errorf("branch %s does not exist", params.Branch)
+	}
+
+	// With one revision, git diff compares that revision to the working tree
+	diff, err := ExecuteGitCommand(ctx, "diff", params.Branch, "--", params.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to compare to branch: %w", err)
+	}
+
+	if diff == "" {
+		return fmt.Sprintf("%s is identical to its version on %s", params.Path, params.Branch), nil
+	}
+
+	return fmt.Sprintf("Diff of %s from %s (-) to the working tree (+):\n\n%s", params.Path, params.Branch, diff), nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCompareToBranch(t *testing.T) {
+	initTestRepo(t)
+	commitFiles(t, "base", map[string]string{"f.txt": "shared\nmain line\n"})
+	runGit(t, "checkout", "-q", "-b", "release")
+	commitFiles(t, "release change", map[string]string{"f.txt": "shared\nrelease line\n"})
+	runGit(t, "checkout", "-q", "main")
+	writeTree(t, map[string]string{"f.txt": "shared\nresolved line\n"})
+
+	input, _ := json.Marshal(CompareToBranchInput{Path: "f.txt", Branch: "release"})
+	got, err := CompareToBranch(context.Background(), input)
+	if err != nil {
+		t.Fatalf("CompareToBranch() error = %v", err)
+	}
+	for _, want := range []string{"Diff of f.txt from release", "-release line", "+resolved line"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("CompareToBranch() = %q, want it to contain %q", got, want)
+		}
+	}
+
+	writeTree(t, map[string]string{"f.txt": "shared\nrelease line\n"})
+	got, err = CompareToBranch(context.Background(), input)
+	if err != nil {
+		t.Fatalf("CompareToBranch() error = %v", err)
+	}
+	if !strings.Contains(got, "identical to its version on release") {
+		t.Errorf("CompareToBranch() = %q, want the file reported identical", got)
+	}
+}
+
+func TestCompareToBranchMissingBranch(t *testing.T) {
+	initTestRepo(t)
+	commitFiles(t, "base", map[string]string{"f.txt": "base\n"})
+
+	input, _ := json.Marshal(CompareToBranchInput{Path: "f.txt", Branch: "no-such-branch"})
+	_, err := CompareToBranch(context.Background(), input)
+	if err == nil || !strings.Contains(err.Error(), "branch no-such-branch does not exist") {
+		t.Errorf("CompareToBranch() error = %v, want a missing branch error", err)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Maximum number of commits listed for each side of a chunk
+const maxProvenanceCommits = 5
+
+var ConflictProvenanceDefinition = ToolDefinition{
+	Name:        "conflict_provenance",
+	Description: "Show which commits and authors on each branch last touched the lines of a conflict chunk: the base code's lines on our branch (HEAD) and the incoming code's lines on their branch (MERGE_HEAD). Answers what each side was trying to do in that chunk.",
+	InputSchema: ConflictProvenanceInputSchema,
+	Function:    ConflictProvenance,
+}
+
+type ConflictProvenanceInput struct {
+	Path    string `json:"path" jsonschema_description:"The path to the conflicted file"`
+	ChunkID int    `json:"chunk_id" jsonschema_description:"The ID of the conflict chunk (zero-indexed)"`
+}
+
+var ConflictProvenanceInputSchema = GenerateSchema[ConflictProvenanceInput]()
+
+func ConflictProvenance(ctx context.Context, input json.RawMessage) (string, error) {
+	var params ConflictProvenanceInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if err := ValidateFileExists(params.Path); err != nil {
+		return "", err
+	}
+	if !IsMergeInProgress(ctx) {
+		return "", fmt.Errorf("no merge is in progress (MERGE_HEAD does not exist)")
+	}
+
+	content, err := os.ReadFile(params.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	chunks, err := FindConflictChunks(string(content))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse conflict chunks: %w", err)
+	}
+	if params.ChunkID < 0 || params.ChunkID >= len(chunks) {
+		return "", fmt.Errorf("chunk ID %d is out of range (found %d chunks)", params.ChunkID, len(chunks))
+	}
+	chunk := chunks[params.ChunkID]
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Provenance of chunk %d in %s (lines %d-%d)\n\n", chunk.ID, params.Path, chunk.StartLine, chunk.EndLine))
+	result.WriteString("Ours (HEAD), base code:\n")
+	result.WriteString(sideProvenance(ctx, params.Path, "HEAD", chunk.BaseCode, chunk.StartLine))
+	result.WriteString("\nTheirs (MERGE_HEAD), incoming code:\n")
+	result.WriteString(sideProvenance(ctx, params.Path, "MERGE_HEAD", chunk.IncomingCode, chunk.StartLine))
+
+	return result.String(), nil
+}
+
+// sideProvenance lists the commits on rev that last touched the lines of code in path
+func sideProvenance(ctx context.Context, path, rev, code string, nearLine int) string {
+	if code == "" {
+		return "  (this side has no lines in the chunk, so there is no provenance)\n"
+	}
+
+	version, err := GetFileVersionAtCommit(ctx, path, rev)
+	if err != nil {
+		return fmt.Sprintf("  (the file does not exist on %s)\n", rev)
+	}
+
+	start, end := findLineRange(strings.Split(version, "\n"), strings.Split(code, "\n"), nearLine)
+	if start == 0 {
+		return fmt.Sprintf("  (these lines were not found in %s's version of the file; they may have been written during resolution)\n", rev)
+	}
+
+	// -s suppresses the patches git log -L prints by default
+	log, err := ExecuteGitCommand(ctx, "log", fmt.Sprintf("--max-count=%d", maxProvenanceCommits), "-s",
+		"--date=short", "--pretty=format:%h %ad %aN: %s", "-L", fmt.Sprintf("%d,%d:%s", start, end, path), rev)
+	if err != nil || log == "" {
+		return fmt.Sprintf("  (no history found for lines %d-%d)\n", start, end)
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("  Commits that changed lines %d-%d on %s (most recent first):\n", start, end, rev))
+	for _, line := range strings.Split(log, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			result.WriteString("    " + line + "\n")
+		}
+	}
+	return result.String()
+}
+
+// findLineRange returns the 1-based range where needle occurs in lines, preferring the
+// occurrence nearest nearLine, or 0, 0 if it does not occur
+func findLineRange(lines, needle []string, nearLine int) (int, int) {
+	best := 0
+	for i := 0; i+len(needle) <= len(lines); i++ {
+		matched := true
+		for j := range needle {
+			if lines[i+j] != needle[j] {
+				matched = false
+				break
+			}
+		}
+		if matched && (best == 0 || abs(i+1-nearLine) < abs(best-nearLine)) {
+			best = i + 1
+		}
+	}
+
+	if best == 0 {
+		return 0, 0
+	}
+	return best, best + len(needle) - 1
+}
+
+// abs returns the absolute value of an int
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
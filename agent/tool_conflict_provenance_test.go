@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestConflictProvenanceReportsEachBranch(t *testing.T) {
+	initTestRepo(t)
+	commitFiles(t, "add config", map[string]string{"config.txt": "name = app\nport = 80\n"})
+
+	commitAs := func(author, message, content string) {
+		t.Helper()
+		writeTree(t, map[string]string{"config.txt": content})
+		runGit(t, "-c", "user.name="+author, "commit", "-q", "-am", message)
+	}
+	runGit(t, "checkout", "-q", "-b", "feature")
+	commitAs("Theo", "use port 9090", "name = app\nport = 9090\n")
+	runGit(t, "checkout", "-q", "main")
+	commitAs("Olive", "use port 8080", "name = app\nport = 8080\n")
+	// The merge conflicts and exits non-zero
+	_ = exec.Command("git", "merge", "feature").Run()
+
+	input, _ := json.Marshal(ConflictProvenanceInput{Path: "config.txt", ChunkID: 0})
+	got, err := ConflictProvenance(context.Background(), input)
+	if err != nil {
+		t.Fatalf("ConflictProvenance() error = %v", err)
+	}
+
+	ours, theirs, found := strings.Cut(got, "Theirs (MERGE_HEAD)")
+	if !found {
+		t.Fatalf("ConflictProvenance() = %q, want a section for each side", got)
+	}
+	if !strings.Contains(ours, "Olive: use port 8080") || strings.Contains(ours, "Theo") {
+		t.Errorf("ours section = %q, want only Olive's commit", ours)
+	}
+	if !strings.Contains(theirs, "Theo: use port 9090") || strings.Contains(theirs, "Olive") {
+		t.Errorf("theirs section = %q, want only Theo's commit", theirs)
+	}
+}
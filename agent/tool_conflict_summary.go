@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+var ConflictSummaryDefinition = ToolDefinition{
+	Name:        "conflict_summary",
+	Description: "List every file that still has conflicts with its number of conflict chunks and conflicting lines, as a JSON array of {path, chunk_count, conflicting_lines}. A cheap overview for planning the order of work before opening individual files.",
+	InputSchema: ConflictSummaryInputSchema,
+	Function:    ConflictSummary,
+}
+
+type ConflictSummaryInput struct {
+	// No parameters needed for this tool
+}
+
+var ConflictSummaryInputSchema = GenerateSchema[ConflictSummaryInput]()
+
+// FileConflictSummary describes the conflicts remaining in one file
+type FileConflictSummary struct {
+	Path             string `json:"path"`
+	ChunkCount       int    `json:"chunk_count"`
+	ConflictingLines int    `json:"conflicting_lines"` // Lines on both sides of every chunk, excluding markers
+	Note             string `json:"note,omitempty"`
+}
+
+func ConflictSummary(ctx context.Context, input json.RawMessage) (string, error) {
+	files, err := UnresolvedFiles(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list conflicted files: %w", err)
+	}
+
+	summaries := []FileConflictSummary{}
+	for _, file := range files {
+		summary := FileConflictSummary{Path: file}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			summary.Note = "not in the working tree, likely deleted on one side (see see_git_status)"
+			summaries = append(summaries, summary)
+			continue
+		}
+
+		chunks, err := FindConflictChunks(string(content))
+		if err != nil {
+			summary.Note = err.Error()
+		}
+		summary.ChunkCount = len(chunks)
+		for _, chunk := range chunks {
+			summary.ConflictingLines += len(splitChunkSide(chunk.BaseCode)) + len(splitChunkSide(chunk.IncomingCode))
+		}
+		summaries = append(summaries, summary)
+	}
+
+	data, err := json.MarshalIndent(summaries, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode summary: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// chunkResolution records how a conflict chunk was resolved
+type chunkResolution struct {
+	Path         string
+	BaseCode     string
+	IncomingCode string
+	Resolution   string
+}
+
+// Chunk resolutions made this session, in order
+var (
+	chunkResolutions   []chunkResolution
+	chunkResolutionsMu sync.Mutex
+)
+
+// recordResolution remembers how a chunk was resolved so identical conflicts can be compared
+func recordResolution(path string, chunk ConflictChunk, resolution string) {
+	// Partial resolutions still contain a conflict and are recorded once it is fully resolved
+	if strings.Contains(resolution, "<<<<<<<") {
+		return
+	}
+
+	chunkResolutionsMu.Lock()
+	defer chunkResolutionsMu.Unlock()
+	chunkResolutions = append(chunkResolutions, chunkResolution{
+		Path:         path,
+		BaseCode:     chunk.BaseCode,
+		IncomingCode: chunk.IncomingCode,
+		Resolution:   resolution,
+	})
+}
+
+var ConsistencyCheckDefinition = ToolDefinition{
+	Name:        "consistency_check",
+	Description: "Find conflicts that appear identically in several places (same base and incoming code) and check that they were resolved the same way. Flags identical conflicts with divergent resolutions, and points out unresolved copies of a conflict that was already resolved elsewhere.",
+	InputSchema: ConsistencyCheckInputSchema,
+	Function:    ConsistencyCheck,
+}
+
+type ConsistencyCheckInput struct {
+	// No parameters needed for this tool
+}
+
+var ConsistencyCheckInputSchema = GenerateSchema[ConsistencyCheckInput]()
+
+// conflictGroup collects the resolved and pending occurrences of one conflict
+type conflictGroup struct {
+	resolutions []chunkResolution
+	pending     []string // "path (chunk N)" of copies that are still conflicted
+}
+
+func ConsistencyCheck(ctx context.Context, input json.RawMessage) (string, error) {
+	groups := make(map[string]*conflictGroup)
+	group := func(base, incoming string) *conflictGroup {
+		key := strings.TrimSpace(base) + "\x00" + strings.TrimSpace(incoming)
+		if groups[key] == nil {
+			groups[key] = &conflictGroup{}
+		}
+		return groups[key]
+	}
+
+	chunkResolutionsMu.Lock()
+	for _, resolution := range chunkResolutions {
+		g := group(resolution.BaseCode, resolution.IncomingCode)
+		g.resolutions = append(g.resolutions, resolution)
+	}
+	chunkResolutionsMu.Unlock()
+
+	files, err := ListUnmergedFiles(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list unmerged files: %w", err)
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		chunks, err := FindConflictChunks(string(content))
+		if err != nil {
+			continue
+		}
+		for _, chunk := range chunks {
+			g := group(chunk.BaseCode, chunk.IncomingCode)
+			g.pending = append(g.pending, fmt.Sprintf("%s (chunk %d)", file, chunk.ID))
+		}
+	}
+
+	var divergent, reusable []string
+	repeated := 0
+	for _, g := range groups {
+		if len(g.resolutions)+len(g.pending) < 2 {
+			continue
+		}
+		repeated++
+
+		distinct := make(map[string][]string)
+		for _, resolution := range g.resolutions {
+			key := strings.TrimSpace(resolution.Resolution)
+			distinct[key] = append(distinct[key], resolution.Path)
+		}
+
+		if len(distinct) > 1 {
+			var variants []string
+			for resolution, paths := range distinct {
+				variants = append(variants, fmt.Sprintf("  In %s:\n```\n%s\n```", strings.Join(paths, ", "), resolution))
+			}
+			sort.Strings(variants)
+			divergent = append(divergent, strings.Join(variants, "\n"))
+		} else if len(distinct) == 1 && len(g.pending) > 0 {
+			for _, paths := range distinct {
+				reusable = append(reusable, fmt.Sprintf("  %s: already resolved in %s",
+					strings.Join(g.pending, ", "), strings.Join(paths, ", ")))
+			}
+		}
+	}
+	sort.Strings(divergent)
+	sort.Strings(reusable)
+
+	if repeated == 0 {
+		return "No conflict appears in more than one place", nil
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Found %d conflict(s) that appear in more than one place.\n", repeated))
+	if len(divergent) > 0 {
+		result.WriteString(fmt.Sprintf("\n%d identical conflict(s) were resolved differently:\n\n", len(divergent)))
+		result.WriteString(strings.Join(divergent, "\n\n"))
+		result.WriteString("\n\nUnless the context genuinely differs, make these resolutions consistent.\n")
+	}
+	if len(reusable) > 0 {
+		result.WriteString("\nThese unresolved chunks match a conflict already resolved elsewhere; reuse that resolution:\n")
+		result.WriteString(strings.Join(reusable, "\n") + "\n")
+	}
+	if len(divergent) == 0 && len(reusable) == 0 {
+		result.WriteString("\nNo divergent resolutions found. Resolve any remaining copies of a repeated conflict the same way.\n")
+	}
+	return result.String(), nil
+}
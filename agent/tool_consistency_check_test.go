@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestConsistencyCheckFlagsDivergentResolutions(t *testing.T) {
+	initTestRepo(t)
+	mergeWithConflicts(t,
+		map[string]string{"a.txt": "timeout = 10\n", "b.txt": "timeout = 10\n", "c.txt": "timeout = 10\n"},
+		map[string]string{"a.txt": "timeout = 20\n", "b.txt": "timeout = 20\n", "c.txt": "timeout = 20\n"},
+		map[string]string{"a.txt": "timeout = 30\n", "b.txt": "timeout = 30\n", "c.txt": "timeout = 30\n"},
+	)
+	chunkResolutions = nil
+	t.Cleanup(func() { chunkResolutions = nil })
+
+	resolve := func(path, strategy string) {
+		t.Helper()
+		input, _ := json.Marshal(ResolveChunkInput{Path: path, ChunkID: 0, Strategy: strategy})
+		if _, err := ResolveChunk(context.Background(), input); err != nil {
+			t.Fatalf("ResolveChunk(%s, %s) error = %v", path, strategy, err)
+		}
+	}
+
+	// The same resolution twice is consistent, and the remaining copy can reuse it
+	resolve("a.txt", "ours")
+	resolve("b.txt", "ours")
+	got, err := ConsistencyCheck(context.Background(), json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("ConsistencyCheck() error = %v", err)
+	}
+	if strings.Contains(got, "resolved differently") || !strings.Contains(got, "c.txt (chunk 0): already resolved in a.txt, b.txt") {
+		t.Errorf("ConsistencyCheck() = %q, want no divergence and c.txt pointed at the existing resolution", got)
+	}
+
+	resolve("c.txt", "theirs")
+	got, err = ConsistencyCheck(context.Background(), json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("ConsistencyCheck() error = %v", err)
+	}
+	for _, want := range []string{
+		"1 identical conflict(s) were resolved differently",
+		"In a.txt, b.txt:\n```\ntimeout = 20\n```",
+		"In c.txt:\n```\ntimeout = 30\n```",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ConsistencyCheck() = %q, want it to contain %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var CreateFileDefinition = ToolDefinition{
+	Name:        "create_file",
+	Description: "Create a new file with the given content, creating parent directories as needed. Fails if the file already exists; use the edit tools for existing files. Useful for add/add conflicts and renames that need a brand-new file.",
+	InputSchema: CreateFileInputSchema,
+	Function:    CreateFile,
+}
+
+type CreateFileInput struct {
+	Path    string `json:"path" jsonschema_description:"The path of the file to create"`
+	Content string `json:"content" jsonschema_description:"The full content of the new file"`
+}
+
+var CreateFileInputSchema = GenerateSchema[CreateFileInput]()
+
+func CreateFile(ctx context.Context, input json.RawMessage) (string, error) {
+	var params CreateFileInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if params.Path == "" {
+		return "", fmt.Errorf("path cannot be empty")
+	}
+	if _, err := os.Stat(params.Path); err == nil {
+		return "", fmt.Errorf("file already exists: %s", params.Path)
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to access file: %w", err)
+	}
+	if err := checkProtected(params.Path); err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(params.Path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create parent directories: %w", err)
+	}
+
+	snapshotFile(params.Path)
+	if err := os.WriteFile(params.Path, []byte(params.Content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	// A new file git ignores would silently be left out of the commit
+	warning := ""
+	if status, err := GetTrackingStatus(ctx, params.Path); err == nil && status == TrackingStatusIgnored {
+		warning = fmt.Sprintf("\n\nWarning: %s is ignored by git, so it will not be included when changes are saved.", params.Path)
+	}
+
+	lines := 0
+	if params.Content != "" {
+		lines = len(strings.Split(strings.TrimSuffix(params.Content, "\n"), "\n"))
+	}
+	return fmt.Sprintf("Created %s with %d line(s)%s", params.Path, lines, warning), nil
+}
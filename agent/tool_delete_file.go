@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -19,7 +20,7 @@ type DeleteFileInput struct {
 
 var DeleteFileInputSchema = GenerateSchema[DeleteFileInput]()
 
-func DeleteFile(input json.RawMessage) (string, error) {
+func DeleteFile(ctx context.Context, input json.RawMessage) (string, error) {
 	deleteFileInput := DeleteFileInput{}
 	err := json.Unmarshal(input, &deleteFileInput)
 	if err != nil {
@@ -39,11 +40,18 @@ func DeleteFile(input json.RawMessage) (string, error) {
 		return "", fmt.Errorf("failed to access file: %w", err)
 	}
 
+	// Warn about (or refuse) deleting files git does not track
+	warning, err := checkTrackedForEdit(ctx, deleteFileInput.Path)
+	if err != nil {
+		return "", err
+	}
+
 	// Delete the file
+	snapshotFile(deleteFileInput.Path)
 	err = os.Remove(deleteFileInput.Path)
 	if err != nil {
 		return "", fmt.Errorf("failed to delete file: %w", err)
 	}
 
-	return fmt.Sprintf("Successfully deleted file %s", deleteFileInput.Path), nil
+	return fmt.Sprintf("Successfully deleted file %s%s", deleteFileInput.Path, warning), nil
 }
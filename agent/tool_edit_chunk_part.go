@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var EditChunkPartDefinition = ToolDefinition{
+	Name:        "edit_chunk_part",
+	Description: "Resolve only part of a large conflict chunk. Replaces the first (or, with from_end, the last) base_lines lines of the chunk's base code and incoming_lines lines of its incoming code with new content, and keeps the rest of both sides inside conflict markers for later resolution. Chunk IDs after this chunk may change if the chunk is fully resolved.",
+	InputSchema: EditChunkPartInputSchema,
+	Function:    EditChunkPart,
+}
+
+type EditChunkPartInput struct {
+	Path          string `json:"path" jsonschema_description:"The path to the file containing the conflict chunk"`
+	ChunkID       int    `json:"chunk_id" jsonschema_description:"The ID of the conflict chunk to partially resolve (zero-indexed)"`
+	BaseLines     int    `json:"base_lines" jsonschema_description:"Number of lines of the chunk's base code that new_content resolves"`
+	IncomingLines int    `json:"incoming_lines" jsonschema_description:"Number of lines of the chunk's incoming code that new_content resolves"`
+	FromEnd       bool   `json:"from_end,omitempty" jsonschema_description:"If true, resolve lines from the bottom of each side instead of the top"`
+	NewContent    string `json:"new_content" jsonschema_description:"The content that replaces the resolved lines of both sides"`
+}
+
+var EditChunkPartInputSchema = GenerateSchema[EditChunkPartInput]()
+
+func EditChunkPart(ctx context.Context, input json.RawMessage) (string, error) {
+	var params EditChunkPartInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if err := ValidateFileExists(params.Path); err != nil {
+		return "", err
+	}
+
+	// Warn about (or refuse) editing files git does not track
+	warning, err := checkTrackedForEdit(ctx, params.Path)
+	if err != nil {
+		return "", err
+	}
+
+	// A resolution containing markers would leave the file broken
+	if line, marker := FindConflictMarker(params.NewContent); line > 0 {
+		return "", fmt.Errorf("new_content has a conflict marker at line %d (%q); remove every conflict marker from the resolution and try again", line, marker)
+	}
+
+	content, err := os.ReadFile(params.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	if err := checkNotBinary(params.Path, content); err != nil {
+		return "", err
+	}
+
+	chunks, err := FindConflictChunks(string(content))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse conflict chunks: %w", err)
+	}
+	if params.ChunkID < 0 || params.ChunkID >= len(chunks) {
+		return "", fmt.Errorf("chunk ID %d is out of range (found %d chunks)", params.ChunkID, len(chunks))
+	}
+	chunk := chunks[params.ChunkID]
+
+	base := splitChunkSide(chunk.BaseCode)
+	incoming := splitChunkSide(chunk.IncomingCode)
+	if params.BaseLines < 0 || params.BaseLines > len(base) {
+		return "", fmt.Errorf("base_lines must be between 0 and %d", len(base))
+	}
+	if params.IncomingLines < 0 || params.IncomingLines > len(incoming) {
+		return "", fmt.Errorf("incoming_lines must be between 0 and %d", len(incoming))
+	}
+	if params.BaseLines == 0 && params.IncomingLines == 0 {
+		return "", fmt.Errorf("base_lines and incoming_lines cannot both be 0")
+	}
+
+	// Reuse the chunk's own marker lines so branch labels are preserved
+	lines := strings.Split(string(content), "\n")
+	markers := conflictMarkers{
+		start:     lines[chunk.StartLine-1],
+		separator: "=======",
+		end:       lines[chunk.EndLine-1],
+	}
+
+	var resolvedBase, remainingBase, resolvedIncoming, remainingIncoming []string
+	if params.FromEnd {
+		remainingBase, resolvedBase = base[:len(base)-params.BaseLines], base[len(base)-params.BaseLines:]
+		remainingIncoming, resolvedIncoming = incoming[:len(incoming)-params.IncomingLines], incoming[len(incoming)-params.IncomingLines:]
+	} else {
+		resolvedBase, remainingBase = base[:params.BaseLines], base[params.BaseLines:]
+		resolvedIncoming, remainingIncoming = incoming[:params.IncomingLines], incoming[params.IncomingLines:]
+	}
+
+	var replacement []string
+	remaining := markers.wrap(remainingBase, remainingIncoming)
+	if params.FromEnd {
+		replacement = append(remaining, params.NewContent)
+	} else {
+		replacement = append([]string{params.NewContent}, remaining...)
+	}
+
+	if err := ReplaceConflictChunk(params.Path, params.ChunkID, strings.Join(replacement, "\n")); err != nil {
+		return "", fmt.Errorf("failed to replace conflict chunk: %w", err)
+	}
+
+	original := strings.Join(append(resolvedBase, resolvedIncoming...), "\n")
+	warning += placeholderWarning(params.Path, original, params.NewContent)
+	warning += recordEdit(params.Path)
+
+	if len(remaining) == 0 {
+		return fmt.Sprintf("Resolved all remaining lines of conflict chunk %d in file %s%s", params.ChunkID, params.Path, warning), nil
+	}
+	return fmt.Sprintf("Resolved %d base and %d incoming lines of conflict chunk %d in file %s; %d base and %d incoming lines remain conflicted%s",
+		len(resolvedBase), len(resolvedIncoming), params.ChunkID, params.Path, len(remainingBase), len(remainingIncoming), warning), nil
+}
+
+// conflictMarkers holds the marker lines of a conflict chunk
+type conflictMarkers struct {
+	start     string
+	separator string
+	end       string
+}
+
+// wrap surrounds the two sides with conflict markers, or returns nil if both sides are empty
+func (m conflictMarkers) wrap(base, incoming []string) []string {
+	if len(base) == 0 && len(incoming) == 0 {
+		return nil
+	}
+
+	lines := []string{m.start}
+	lines = append(lines, base...)
+	lines = append(lines, m.separator)
+	lines = append(lines, incoming...)
+	return append(lines, m.end)
+}
+
+// splitChunkSide splits one side of a conflict chunk into lines, treating empty code as no lines
+func splitChunkSide(code string) []string {
+	if code == "" {
+		return nil
+	}
+	return strings.Split(code, "\n")
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestEditChunkPart(t *testing.T) {
+	const content = "a\n<<<<<<< HEAD\nours1\nours2\n=======\ntheirs1\ntheirs2\n>>>>>>> b\nz\n"
+	path := writeTempFile(t, "f.txt", content)
+
+	input, _ := json.Marshal(EditChunkPartInput{Path: path, ChunkID: 0, BaseLines: 1, IncomingLines: 1, NewContent: "merged1"})
+	if _, err := EditChunkPart(context.Background(), input); err != nil {
+		t.Fatalf("EditChunkPart() error = %v", err)
+	}
+	want := "a\nmerged1\n<<<<<<< HEAD\nours2\n=======\ntheirs2\n>>>>>>> b\nz\n"
+	if got := readTempFile(t, path); got != want {
+		t.Errorf("content = %q, want %q", got, want)
+	}
+}
+
+func TestEditChunkPartRejectsMarkers(t *testing.T) {
+	const content = "a\n<<<<<<< HEAD\nours1\nours2\n=======\ntheirs1\n>>>>>>> b\nz\n"
+	path := writeTempFile(t, "f.txt", content)
+
+	input, _ := json.Marshal(EditChunkPartInput{Path: path, ChunkID: 0, BaseLines: 1, IncomingLines: 1, NewContent: "ours1\n=======\ntheirs1"})
+	if _, err := EditChunkPart(context.Background(), input); err == nil || !strings.Contains(err.Error(), "conflict marker") {
+		t.Errorf("EditChunkPart() error = %v, want a conflict marker error", err)
+	}
+	if got := readTempFile(t, path); got != content {
+		t.Errorf("file changed to %q after a rejected edit", got)
+	}
+}
+
+func TestEditChunkPartRejectsBinaryFiles(t *testing.T) {
+	const content = "a\x00\n<<<<<<< HEAD\nours\n=======\ntheirs\n>>>>>>> b\n"
+	path := writeTempFile(t, "f.txt", content)
+
+	input, _ := json.Marshal(EditChunkPartInput{Path: path, ChunkID: 0, BaseLines: 1, IncomingLines: 1, NewContent: "merged"})
+	if _, err := EditChunkPart(context.Background(), input); err == nil {
+		t.Error("EditChunkPart() succeeded on a binary file, want an error")
+	}
+	if got := readTempFile(t, path); got != content {
+		t.Errorf("binary file changed to %q", got)
+	}
+}
@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"sort"
+	"strings"
 )
 
 var EditFileChunkDefinition = ToolDefinition{
 	Name:        "edit_file_chunk",
-	Description: "Resolve a specific conflict chunk in a file by replacing it with new content. Identifies the chunk by its ID number (starting from 0 for the first chunk at the top of the file).",
+	Description: "Resolve a specific conflict chunk in a file by replacing it with new content. Identifies the chunk by its ID number (starting from 0 for the first chunk at the top of the file). To resolve several chunks of the file at once, pass edits instead; they are applied from the bottom up so the IDs from see_file_chunks stay valid.",
 	InputSchema: EditFileChunkInputSchema,
 	Function:    EditFileChunk,
 }
@@ -16,21 +20,52 @@ type EditFileChunkInput struct {
 	Path       string `json:"path" jsonschema_description:"The path to the file containing the conflict chunk"`
 	ChunkID    int    `json:"chunk_id" jsonschema_description:"The ID of the conflict chunk to edit (zero-indexed, with chunk 0 being the first chunk from the top of the file)"`
 	NewContent string `json:"new_content" jsonschema_description:"The content to replace the entire conflict chunk with"`
+
+	Edits []ChunkEdit `json:"edits,omitempty" jsonschema_description:"Optional list of chunks to resolve in one call instead of chunk_id and new_content. IDs refer to the file as it is now and must be unique."`
+
+	NormalizeIndent bool `json:"normalize_indent,omitempty" jsonschema_description:"If true, reindent new_content to match the file's indent style (tabs or N spaces) before writing. Use this when the two sides were indented differently."`
+}
+
+// ChunkEdit is one chunk resolution in a batched edit_file_chunk call
+type ChunkEdit struct {
+	ChunkID    int    `json:"chunk_id" jsonschema_description:"The ID of the conflict chunk to replace"`
+	NewContent string `json:"new_content" jsonschema_description:"The content to replace the conflict chunk with"`
 }
 
 var EditFileChunkInputSchema = GenerateSchema[EditFileChunkInput]()
 
-func EditFileChunk(input json.RawMessage) (string, error) {
+func EditFileChunk(ctx context.Context, input json.RawMessage) (string, error) {
 	var params EditFileChunkInput
 	if err := json.Unmarshal(input, &params); err != nil {
 		return "", fmt.Errorf("failed to parse parameters: %w", err)
 	}
 
+	edits := params.Edits
+	if len(edits) == 0 {
+		edits = []ChunkEdit{{ChunkID: params.ChunkID, NewContent: params.NewContent}}
+	} else if params.NewContent != "" {
+		return "", fmt.Errorf("pass either edits or chunk_id and new_content, not both")
+	}
+
 	// Validate file exists
 	if err := ValidateFileExists(params.Path); err != nil {
 		return "", err
 	}
 
+	// Warn about (or refuse) editing files git does not track
+	warning, err := checkTrackedForEdit(ctx, params.Path)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(params.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	if err := checkNotBinary(params.Path, content); err != nil {
+		return "", err
+	}
+
 	// Validate that file has conflict markers
 	hasConflicts, err := HasMergeConflicts(params.Path)
 	if err != nil {
@@ -40,11 +75,66 @@ func EditFileChunk(input json.RawMessage) (string, error) {
 		return "", fmt.Errorf("no merge conflicts found in file: %s", params.Path)
 	}
 
-	// Replace the conflict chunk
-	if err := ReplaceConflictChunk(params.Path, params.ChunkID, params.NewContent); err != nil {
-		return "", fmt.Errorf("failed to replace conflict chunk: %w", err)
+	chunks, err := FindConflictChunks(string(content))
+	if err != nil {
+		return "", err
+	}
+
+	// Check every edit before applying any, so a bad batch leaves the file untouched
+	seen := make(map[int]bool)
+	for i := range edits {
+		edit := &edits[i]
+		if edit.ChunkID < 0 || edit.ChunkID >= len(chunks) {
+			return "", fmt.Errorf("chunk ID %d is out of range (found %d chunks)", edit.ChunkID, len(chunks))
+		}
+		if seen[edit.ChunkID] {
+			return "", fmt.Errorf("chunk ID %d appears more than once", edit.ChunkID)
+		}
+		seen[edit.ChunkID] = true
+
+		// Match the indentation of the rest of the file if requested
+		if params.NormalizeIndent {
+			if style, ok := detectIndentStyle(strings.Split(string(content), "\n")); ok {
+				edit.NewContent = reindent(edit.NewContent, style)
+			}
+		}
+
+		// A resolution containing markers would leave the file broken
+		if line, marker := FindConflictMarker(edit.NewContent); line > 0 {
+			return "", fmt.Errorf("new_content for chunk %d has a conflict marker at line %d (%q); remove every conflict marker from the resolution and try again", edit.ChunkID, line, marker)
+		}
+	}
+
+	// Apply from the bottom up so replacing one chunk never renumbers the others
+	sort.Slice(edits, func(i, j int) bool { return edits[i].ChunkID > edits[j].ChunkID })
+	var results []string
+	for _, edit := range edits {
+		if err := ReplaceConflictChunk(params.Path, edit.ChunkID, edit.NewContent); err != nil {
+			if len(params.Edits) == 0 {
+				return "", fmt.Errorf("failed to replace conflict chunk: %w", err)
+			}
+			results = append(results, fmt.Sprintf("chunk %d: failed: %v", edit.ChunkID, err))
+			continue
+		}
+		results = append(results, fmt.Sprintf("chunk %d: replaced", edit.ChunkID))
+
+		// Check the resolution against both sides of the chunk
+		original := chunks[edit.ChunkID].BaseCode + "\n" + chunks[edit.ChunkID].IncomingCode
+		warning += placeholderWarning(params.Path, original, edit.NewContent)
+	}
+
+	// Remind the agent of the chunks still left in this file
+	if content, err := os.ReadFile(params.Path); err == nil {
+		if chunks, err := FindConflictChunks(string(content)); err == nil && len(chunks) > 0 {
+			warning += fmt.Sprintf("\n\nNote: %s still has %d unresolved conflict chunk(s); continue with see_file_chunks.", params.Path, len(chunks))
+		}
 	}
 
-	return fmt.Sprintf("Successfully replaced conflict chunk %d in file %s", 
-		params.ChunkID, params.Path), nil
-}
\ No newline at end of file
+	warning += recordEdit(params.Path)
+
+	if len(params.Edits) == 0 {
+		return fmt.Sprintf("Successfully replaced conflict chunk %d in file %s%s",
+			params.ChunkID, params.Path, warning), nil
+	}
+	return fmt.Sprintf("Resolved chunks in %s from the bottom up:\n%s%s", params.Path, strings.Join(results, "\n"), warning), nil
+}
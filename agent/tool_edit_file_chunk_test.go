@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const twoChunks = "a\n<<<<<<< HEAD\nours1\n=======\ntheirs1\n>>>>>>> b\nm\n<<<<<<< HEAD\nours2\n=======\ntheirs2\n>>>>>>> b\nz\n"
+
+func TestEditFileChunkRejectsMarkers(t *testing.T) {
+	for _, newContent := range []string{"ours1\n=======\ntheirs1", "<<<<<<< HEAD\nours1", "ours1\n>>>>>>> b"} {
+		path := writeTempFile(t, "f.txt", twoChunks)
+		input, _ := json.Marshal(EditFileChunkInput{Path: path, ChunkID: 0, NewContent: newContent})
+		if _, err := EditFileChunk(context.Background(), input); err == nil || !strings.Contains(err.Error(), "conflict marker") {
+			t.Errorf("EditFileChunk(%q) error = %v, want a conflict marker error", newContent, err)
+		}
+		if got := readTempFile(t, path); got != twoChunks {
+			t.Errorf("file changed to %q after a rejected edit", got)
+		}
+	}
+}
+
+func TestEditFileChunkWarnsAboutRemainingChunks(t *testing.T) {
+	path := writeTempFile(t, "f.txt", twoChunks)
+
+	input, _ := json.Marshal(EditFileChunkInput{Path: path, ChunkID: 0, NewContent: "resolved1"})
+	result, err := EditFileChunk(context.Background(), input)
+	if err != nil {
+		t.Fatalf("EditFileChunk() error = %v", err)
+	}
+	if !strings.Contains(result, "still has 1 unresolved conflict chunk(s)") {
+		t.Errorf("EditFileChunk() = %q, want a note about the remaining chunk", result)
+	}
+
+	input, _ = json.Marshal(EditFileChunkInput{Path: path, ChunkID: 0, NewContent: "resolved2"})
+	result, err = EditFileChunk(context.Background(), input)
+	if err != nil {
+		t.Fatalf("EditFileChunk() error = %v", err)
+	}
+	if strings.Contains(result, "unresolved conflict chunk") {
+		t.Errorf("EditFileChunk() = %q, want no note once every chunk is resolved", result)
+	}
+	if got, want := readTempFile(t, path), "a\nresolved1\nm\nresolved2\nz\n"; got != want {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -9,7 +10,7 @@ import (
 
 var EditFileLineDefinition = ToolDefinition{
 	Name:        "edit_file_line",
-	Description: "Edit a specific line or range of lines in a file. Replaces the content of the specified line(s) with new content. Line numbers are 1-indexed.",
+	Description: "Edit a specific line or range of lines in a file. Replaces the content of the specified line(s) with new content, or with insert set, inserts new content before start_line without replacing anything. Line numbers are 1-indexed.",
 	InputSchema: EditFileLineInputSchema,
 	Function:    EditFileLine,
 }
@@ -19,11 +20,14 @@ type EditFileLineInput struct {
 	StartLine  int    `json:"start_line" jsonschema_description:"The starting line number to replace (1-indexed)"`
 	EndLine    int    `json:"end_line,omitempty" jsonschema_description:"Optional end line number for replacing a range (inclusive, 1-indexed). If omitted, only the start line is replaced."`
 	NewContent string `json:"new_content" jsonschema_description:"The new content to replace the specified line(s) with. Can contain multiple lines (use \n for line breaks)."`
+	Insert     bool   `json:"insert,omitempty" jsonschema_description:"Optional. If true, inserts new_content before start_line and shifts existing lines down instead of replacing them. Use one past the last line to append at the end of the file. end_line must be omitted."`
+
+	AllowOutsideConflicts bool `json:"allow_outside_conflicts,omitempty" jsonschema_description:"Allow editing lines outside the original conflict regions when strict region mode is enabled. Only use this when the edit is required by the resolution."`
 }
 
 var EditFileLineInputSchema = GenerateSchema[EditFileLineInput]()
 
-func EditFileLine(input json.RawMessage) (string, error) {
+func EditFileLine(ctx context.Context, input json.RawMessage) (string, error) {
 	var params EditFileLineInput
 	if err := json.Unmarshal(input, &params); err != nil {
 		return "", fmt.Errorf("failed to parse parameters: %w", err)
@@ -39,6 +43,10 @@ func EditFileLine(input json.RawMessage) (string, error) {
 		return "", fmt.Errorf("start_line must be at least 1")
 	}
 
+	if params.Insert && params.EndLine != 0 {
+		return "", fmt.Errorf("end_line cannot be used with insert, which never replaces lines")
+	}
+
 	// If EndLine is not specified or is 0, set it to StartLine (edit only one line)
 	if params.EndLine == 0 {
 		params.EndLine = params.StartLine
@@ -49,23 +57,45 @@ func EditFileLine(input json.RawMessage) (string, error) {
 		return "", fmt.Errorf("end_line cannot be less than start_line")
 	}
 
+	// Refuse edits away from the conflicts in strict region mode
+	if !params.AllowOutsideConflicts {
+		if err := checkConflictRegion(params.Path, params.StartLine, params.EndLine); err != nil {
+			return "", err
+		}
+	}
+
+	// Warn about (or refuse) editing files git does not track
+	warning, err := checkTrackedForEdit(ctx, params.Path)
+	if err != nil {
+		return "", err
+	}
+
 	// Read file content
 	content, err := os.ReadFile(params.Path)
 	if err != nil {
 		return "", fmt.Errorf("failed to read file: %w", err)
 	}
+	if err := checkNotBinary(params.Path, content); err != nil {
+		return "", err
+	}
 
-	lines := strings.Split(string(content), "\n")
+	// Edit with LF line endings and restore the file's own line endings when writing
+	text, eol := toLF(string(content))
+	lines := strings.Split(text, "\n")
+
+	if params.Insert {
+		return insertLines(params, lines, eol, warning)
+	}
 
 	// Check if startLine is out of range
 	if params.StartLine > len(lines) {
-		return "", fmt.Errorf("start_line %d is beyond the file length of %d lines", 
+		return "", fmt.Errorf("start_line %d is beyond the file length of %d lines",
 			params.StartLine, len(lines))
 	}
 
 	// Check if endLine is out of range
 	if params.EndLine > len(lines) {
-		return "", fmt.Errorf("end_line %d is beyond the file length of %d lines", 
+		return "", fmt.Errorf("end_line %d is beyond the file length of %d lines",
 			params.EndLine, len(lines))
 	}
 
@@ -75,7 +105,7 @@ func EditFileLine(input json.RawMessage) (string, error) {
 
 	// The lines to replace
 	newLines := strings.Split(params.NewContent, "\n")
-	
+
 	// Construct the new content
 	result := append(append([]string{}, lines[:startIndex]...), newLines...)
 	if endIndex < len(lines)-1 {
@@ -83,11 +113,14 @@ func EditFileLine(input json.RawMessage) (string, error) {
 	}
 
 	// Write the updated content back to the file
-	err = os.WriteFile(params.Path, []byte(strings.Join(result, "\n")), 0644)
+	snapshotFile(params.Path)
+	err = os.WriteFile(params.Path, []byte(fromLF(strings.Join(result, "\n"), eol)), 0644)
 	if err != nil {
 		return "", fmt.Errorf("failed to write updated content to file: %w", err)
 	}
 
+	shiftConflictRegions(params.Path, params.StartLine, params.EndLine, len(newLines))
+
 	// Build result message
 	var actionMsg string
 	if params.StartLine == params.EndLine {
@@ -96,6 +129,40 @@ func EditFileLine(input json.RawMessage) (string, error) {
 		actionMsg = fmt.Sprintf("lines %d-%d", params.StartLine, params.EndLine)
 	}
 
-	return fmt.Sprintf("Successfully edited %s in file %s", 
-		actionMsg, params.Path), nil
-}
\ No newline at end of file
+	warning += placeholderWarning(params.Path, strings.Join(lines[startIndex:endIndex+1], "\n"), params.NewContent)
+	warning += recordEdit(params.Path)
+
+	return fmt.Sprintf("Successfully edited %s in file %s%s",
+		actionMsg, params.Path, warning), nil
+}
+
+// insertLines inserts new content before the start line, where one past the last line appends
+func insertLines(params EditFileLineInput, lines []string, eol string, warning string) (string, error) {
+	// A trailing newline leaves an empty final element, which is not a line to insert after
+	lineCount := countLines(strings.Join(lines, "\n"))
+	if params.StartLine > lineCount+1 {
+		return "", fmt.Errorf("start_line %d is beyond the end of the file; use %d to append after its last line",
+			params.StartLine, lineCount+1)
+	}
+
+	startIndex := params.StartLine - 1
+	newLines := strings.Split(params.NewContent, "\n")
+	result := append(append(append([]string{}, lines[:startIndex]...), newLines...), lines[startIndex:]...)
+
+	snapshotFile(params.Path)
+	if err := os.WriteFile(params.Path, []byte(fromLF(strings.Join(result, "\n"), eol)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write updated content to file: %w", err)
+	}
+
+	shiftConflictRegions(params.Path, params.StartLine, params.StartLine-1, len(newLines))
+
+	warning += placeholderWarning(params.Path, "", params.NewContent)
+	warning += recordEdit(params.Path)
+
+	position := fmt.Sprintf("before line %d", params.StartLine)
+	if params.StartLine == lineCount+1 {
+		position = "at the end"
+	}
+	return fmt.Sprintf("Successfully inserted %d line(s) %s of file %s%s",
+		len(newLines), position, params.Path, warning), nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Maximum number of lines of each file's common ancestor included in an export
+const maxExportAncestorLines = 200
+
+var ExportConflictsDefinition = ToolDefinition{
+	Name:        "export_conflicts",
+	Description: "Write a single Markdown document listing every file that still has conflicts, each chunk's base and incoming code, and the file's common ancestor version, for offline human review. Choose a path outside the repository (or an ignored one) so the document is not committed by git_save_changes.",
+	InputSchema: ExportConflictsInputSchema,
+	Function:    ExportConflicts,
+}
+
+type ExportConflictsInput struct {
+	Path string `json:"path" jsonschema_description:"The path to write the Markdown review document to"`
+}
+
+var ExportConflictsInputSchema = GenerateSchema[ExportConflictsInput]()
+
+func ExportConflicts(ctx context.Context, input json.RawMessage) (string, error) {
+	var params ExportConflictsInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if params.Path == "" {
+		return "", fmt.Errorf("output path cannot be empty")
+	}
+
+	files, err := ListUnmergedFiles(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list conflicted files: %w", err)
+	}
+
+	document, fileCount, chunkCount := BuildConflictReport(ctx, files, time.Now())
+	if err := os.WriteFile(params.Path, []byte(document), 0644); err != nil {
+		return "", fmt.Errorf("failed to write review document: %w", err)
+	}
+
+	return fmt.Sprintf("Exported %d conflict chunk(s) across %d file(s) to %s", chunkCount, fileCount, params.Path), nil
+}
+
+// BuildConflictReport renders the conflicts in files as a Markdown document and returns it
+// along with the number of files and chunks it covers. Files without conflict markers are skipped.
+func BuildConflictReport(ctx context.Context, files []string, generatedAt time.Time) (string, int, int) {
+	var doc strings.Builder
+	doc.WriteString("# GitSynth conflict review\n\n")
+	doc.WriteString(fmt.Sprintf("Generated %s.\n", generatedAt.Format(time.RFC1123)))
+
+	fileCount, chunkCount := 0, 0
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		chunks, err := FindConflictChunks(string(content))
+		if err != nil || len(chunks) == 0 {
+			continue
+		}
+
+		fileCount++
+		chunkCount += len(chunks)
+		doc.WriteString(fmt.Sprintf("\n## %s\n\n%d conflict chunk(s).\n", file, len(chunks)))
+
+		for _, chunk := range chunks {
+			doc.WriteString(fmt.Sprintf("\n### Chunk %d (lines %d-%d)\n\n", chunk.ID, chunk.StartLine, chunk.EndLine))
+			doc.WriteString(fmt.Sprintf("Base code (ours: %s):\n\n```\n%s\n```\n\n", chunk.BaseLabel, chunk.BaseCode))
+			doc.WriteString(fmt.Sprintf("Incoming code (theirs: %s):\n\n```\n%s\n```\n", chunk.IncomingLabel, chunk.IncomingCode))
+		}
+
+		// Stage 1 is missing when the file was added on both sides
+		ancestor, err := GetFileVersionAtStage(ctx, file, 1)
+		if err != nil {
+			doc.WriteString("\n### Common ancestor\n\n(no common ancestor, the file was added on both sides)\n")
+			continue
+		}
+		lines := strings.Split(ancestor, "\n")
+		note := ""
+		if len(lines) > maxExportAncestorLines {
+			note = fmt.Sprintf("\n(truncated to the first %d of %d lines)\n", maxExportAncestorLines, len(lines))
+			lines = lines[:maxExportAncestorLines]
+		}
+		doc.WriteString(fmt.Sprintf("\n### Common ancestor\n\n```\n%s\n```\n%s", strings.Join(lines, "\n"), note))
+	}
+
+	if fileCount == 0 {
+		doc.WriteString("\nNo conflicts remain.\n")
+	}
+	return doc.String(), fileCount, chunkCount
+}
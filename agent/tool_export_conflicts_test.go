@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestExportConflicts(t *testing.T) {
+	initTestRepo(t)
+	mergeWithConflicts(t,
+		map[string]string{
+			"one.txt": "a\n1\n2\n3\n4\n5\nc\n",
+			"two.txt": "base two\n",
+		},
+		map[string]string{
+			"one.txt": "ours a\n1\n2\n3\n4\n5\nours c\n",
+			"two.txt": "ours two\n",
+		},
+		map[string]string{
+			"one.txt": "theirs a\n1\n2\n3\n4\n5\ntheirs c\n",
+			"two.txt": "theirs two\n",
+		},
+	)
+
+	input, _ := json.Marshal(ExportConflictsInput{Path: "review.md"})
+	got, err := ExportConflicts(context.Background(), input)
+	if err != nil {
+		t.Fatalf("ExportConflicts() error = %v", err)
+	}
+	if want := "Exported 3 conflict chunk(s) across 2 file(s) to review.md"; got != want {
+		t.Errorf("ExportConflicts() = %q, want %q", got, want)
+	}
+
+	document := readTempFile(t, "review.md")
+	for _, want := range []string{
+		"## one.txt\n\n2 conflict chunk(s).",
+		"### Chunk 0 (lines 1-5)",
+		"### Chunk 1 (lines 11-15)",
+		"```\nours a\n```", "```\ntheirs a\n```",
+		"```\nours c\n```", "```\ntheirs c\n```",
+		"## two.txt\n\n1 conflict chunk(s).",
+		"```\nours two\n```", "```\ntheirs two\n```",
+		"### Common ancestor\n\n```\nbase two\n```",
+	} {
+		if !strings.Contains(document, want) {
+			t.Errorf("review.md = %q, want it to contain %q", document, want)
+		}
+	}
+}
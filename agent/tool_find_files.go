@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var FindFilesDefinition = ToolDefinition{
+	Name:        "find_files",
+	Description: "Find files anywhere in the project whose name matches a glob pattern (e.g. '*.config.js', 'Makefile', 'test_*.py'). Hidden directories and .gitignore'd files are skipped. This searches file names only; use search_symbol to search file contents.",
+	InputSchema: FindFilesInputSchema,
+	Function:    FindFiles,
+}
+
+type FindFilesInput struct {
+	Pattern string `json:"pattern" jsonschema_description:"Glob pattern to match against file names (e.g. '*.config.js')"`
+}
+
+var FindFilesInputSchema = GenerateSchema[FindFilesInput]()
+
+func FindFiles(ctx context.Context, input json.RawMessage) (string, error) {
+	var params FindFilesInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if params.Pattern == "" {
+		return "", fmt.Errorf("pattern cannot be empty")
+	}
+
+	// Validate the pattern up front so a typo is reported rather than matching nothing
+	if _, err := filepath.Match(params.Pattern, ""); err != nil {
+		return "", fmt.Errorf("invalid glob pattern: %w", err)
+	}
+
+	files, err := findMatchingFiles(ctx, params.Pattern, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to find files: %w", err)
+	}
+
+	if len(files) == 0 {
+		return fmt.Sprintf("No files found matching '%s'", params.Pattern), nil
+	}
+
+	sort.Strings(files)
+	return fmt.Sprintf("Found %d files matching '%s':\n%s", len(files), params.Pattern,
+		strings.Join(files, "\n")), nil
+}
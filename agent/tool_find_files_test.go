@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFindFiles(t *testing.T) {
+	initTestRepo(t)
+	writeTree(t, map[string]string{
+		"Makefile":                   "",
+		"webpack.config.js":          "",
+		"src/app.js":                 "",
+		"src/test_app.py":            "",
+		"src/build/Makefile":         "",
+		"packages/ui/jest.config.js": "",
+		"packages/ui/test_button.py": "",
+		".hidden/test_secret.py":     "",
+	})
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"Makefile", []string{"Makefile", "src/build/Makefile"}},
+		{"*.config.js", []string{"packages/ui/jest.config.js", "webpack.config.js"}},
+		{"test_*.py", []string{"packages/ui/test_button.py", "src/test_app.py"}},
+		{"*.rb", nil},
+		{"app", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			input, _ := json.Marshal(FindFilesInput{Pattern: tt.pattern})
+			got, err := FindFiles(context.Background(), input)
+			if err != nil {
+				t.Fatalf("FindFiles(%q) error = %v", tt.pattern, err)
+			}
+			if tt.want == nil {
+				if !strings.HasPrefix(got, "No files found") {
+					t.Errorf("FindFiles(%q) = %q, want no matches", tt.pattern, got)
+				}
+				return
+			}
+			lines := strings.Split(got, "\n")
+			if gotFiles := lines[1:]; strings.Join(gotFiles, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("FindFiles(%q) = %v, want %v", tt.pattern, gotFiles, tt.want)
+			}
+		})
+	}
+}
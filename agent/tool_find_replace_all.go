@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -14,13 +16,16 @@ type FindReplaceAllParams struct {
 
 	// The text to replace matches with
 	Replace string `json:"replace" jsonschema:"description=The text to replace matches with."`
-	
+
 	// Whether the find text should be treated as a regex pattern
 	IsRegex bool `json:"is_regex,omitempty" jsonschema:"description=If true, the find text will be treated as a regular expression pattern."`
-	
+
 	// Optional glob pattern to filter which files to search in (e.g. "*.go", "src/**/*.ts")
-	FilePattern string `json:"file_pattern,omitempty" jsonschema:"description=Optional glob pattern to filter which files to search in (e.g. '*.go', 'src/**/*.ts')."`
-	
+	FilePattern string `json:"file_pattern,omitempty" jsonschema:"description=Optional glob patterns to filter which files to search in (e.g. '*.go' or 'src/**/*.ts'). Separate several patterns with commas. Patterns with a slash match the path from the repository root and ** matches any number of directories."`
+
+	// Optional glob patterns for files to skip
+	ExcludePattern string `json:"exclude_pattern,omitempty" jsonschema:"description=Optional comma-separated glob patterns for files to skip (e.g. 'vendor/**')."`
+
 	// Whether the search should be case-sensitive
 	CaseSensitive bool `json:"case_sensitive,omitempty" jsonschema:"description=Whether the search should be case-sensitive. Defaults to false."`
 }
@@ -34,7 +39,7 @@ var FindReplaceAllDefinition = ToolDefinition{
 - Shows a preview of changes before applying them
 - Returns a summary of changes made`,
 	InputSchema: GenerateSchema[FindReplaceAllParams](),
-	Function: func(input json.RawMessage) (string, error) {
+	Function: func(ctx context.Context, input json.RawMessage) (string, error) {
 		var params FindReplaceAllParams
 		if err := json.Unmarshal(input, &params); err != nil {
 			return "", fmt.Errorf("failed to parse find and replace parameters: %w", err)
@@ -51,7 +56,11 @@ var FindReplaceAllDefinition = ToolDefinition{
 		}
 
 		// Search for matches using the same logic as search_symbol
-		matches, err := grep(params.Find, includePattern, params.CaseSensitive)
+		searchPattern := params.Find
+		if !params.IsRegex {
+			searchPattern = regexp.QuoteMeta(params.Find)
+		}
+		matches, err := grepWithContext(ctx, searchPattern, includePattern, params.ExcludePattern, params.CaseSensitive, 0, 0)
 		if err != nil {
 			return "", fmt.Errorf("search failed: %w", err)
 		}
@@ -68,6 +77,16 @@ var FindReplaceAllDefinition = ToolDefinition{
 			return details.String(), nil
 		}
 
+		// Substitute with the same pattern and case sensitivity used for the search
+		substitutePattern := searchPattern
+		if !params.CaseSensitive {
+			substitutePattern = "(?i)" + substitutePattern
+		}
+		findRegex, err := regexp.Compile(substitutePattern)
+		if err != nil {
+			return "", fmt.Errorf("invalid regex pattern: %w", err)
+		}
+
 		// Group matches by file
 		fileMatches := make(map[string][]GrepMatch)
 		for _, match := range matches {
@@ -77,38 +96,42 @@ var FindReplaceAllDefinition = ToolDefinition{
 		// Process each file
 		var output strings.Builder
 		output.WriteString(fmt.Sprintf("Found matches in %d files.\n\n", len(fileMatches)))
-		
+
 		filesModified := 0
 		replacementsCount := 0
 
-		for filePath, matches := range fileMatches {
+		for filePath := range fileMatches {
+			if checkProtected(filePath) != nil {
+				output.WriteString(fmt.Sprintf("Skipped %s (protected by the repository's GitSynth config)\n", filePath))
+				continue
+			}
+
 			// Read the entire file
 			content, err := os.ReadFile(filePath)
 			if err != nil {
 				return "", fmt.Errorf("failed to read file %s: %w", filePath, err)
 			}
 
-			// Create new content with replacements
-			fileContent := string(content)
+			// Create new content with replacements, working with LF line endings
+			fileContent, eol := toLF(string(content))
 			newContent := fileContent
 
-			// Perform the replacement
-			var replaceFunc func(string, string, string) string
+			// Perform the replacement, counting the substitutions actually made
+			replaced := len(findRegex.FindAllString(fileContent, -1))
 			if params.IsRegex {
-				replaceFunc = strings.ReplaceAll // For now using simple replace, could be enhanced with regex
+				newContent = findRegex.ReplaceAllString(fileContent, params.Replace)
 			} else {
-				replaceFunc = strings.ReplaceAll
+				newContent = findRegex.ReplaceAllLiteralString(fileContent, params.Replace)
 			}
 
-			newContent = replaceFunc(fileContent, params.Find, params.Replace)
-
 			// If content changed, write it back
 			if newContent != fileContent {
-				if err := os.WriteFile(filePath, []byte(newContent), 0644); err != nil {
+				snapshotFile(filePath)
+				if err := os.WriteFile(filePath, []byte(fromLF(newContent, eol)), 0644); err != nil {
 					return "", fmt.Errorf("failed to write changes to file %s: %w", filePath, err)
 				}
 				filesModified++
-				replacementsCount += len(matches)
+				replacementsCount += replaced
 
 				// Report the changes for this file
 				relPath := filePath
@@ -117,7 +140,10 @@ var FindReplaceAllDefinition = ToolDefinition{
 						relPath = rel
 					}
 				}
-				output.WriteString(fmt.Sprintf("Modified %s (%d replacements)\n", relPath, len(matches)))
+				output.WriteString(fmt.Sprintf("Modified %s (%d replacements)\n", relPath, replaced))
+				if warning := placeholderWarning(relPath, fileContent, newContent); warning != "" {
+					output.WriteString(strings.TrimPrefix(warning, "\n") + "\n")
+				}
 			}
 		}
 
@@ -128,4 +154,4 @@ var FindReplaceAllDefinition = ToolDefinition{
 
 		return output.String(), nil
 	},
-}
\ No newline at end of file
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFindReplaceAllCountsReplacements(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      FindReplaceAllParams
+		wantContent string
+		wantCount   string
+	}{
+		{
+			name:        "two literal matches on one line",
+			params:      FindReplaceAllParams{Find: "foo", Replace: "baz", CaseSensitive: true},
+			wantContent: "baz := baz + 1\nbar\nbaz\n",
+			wantCount:   "Total replacements made: 3",
+		},
+		{
+			name:        "two regex matches on one line",
+			params:      FindReplaceAllParams{Find: `f(o+)`, Replace: "b$1", IsRegex: true, CaseSensitive: true},
+			wantContent: "boo := boo + 1\nbar\nboo\n",
+			wantCount:   "Total replacements made: 3",
+		},
+		{
+			name:        "no-op replacement",
+			params:      FindReplaceAllParams{Find: "foo", Replace: "foo", CaseSensitive: true},
+			wantContent: "foo := foo + 1\nbar\nfoo\n",
+			wantCount:   "Total replacements made: 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			writeTree(t, map[string]string{"main.go": "foo := foo + 1\nbar\nfoo\n"})
+
+			input, _ := json.Marshal(tt.params)
+			result, err := FindReplaceAllDefinition.Function(context.Background(), input)
+			if err != nil {
+				t.Fatalf("find_replace_all error = %v", err)
+			}
+			if !strings.Contains(result, tt.wantCount) {
+				t.Errorf("find_replace_all = %q, want it to report %q", result, tt.wantCount)
+			}
+			if got := readTempFile(t, "main.go"); got != tt.wantContent {
+				t.Errorf("main.go = %q, want %q", got, tt.wantContent)
+			}
+		})
+	}
+}
+
+func TestFindReplaceAllDefaultCaseInsensitive(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeTree(t, map[string]string{
+		"mixed.go": "Foo := FOO + foo\n",
+		"other.go": "bar\n",
+	})
+
+	for _, params := range []FindReplaceAllParams{
+		{Find: "foo", Replace: "baz"},
+		{Find: "f[o]+", Replace: "baz", IsRegex: true},
+	} {
+		writeTree(t, map[string]string{"mixed.go": "Foo := FOO + foo\n"})
+		input, _ := json.Marshal(params)
+		result, err := FindReplaceAllDefinition.Function(context.Background(), input)
+		if err != nil {
+			t.Fatalf("find_replace_all(%+v) error = %v", params, err)
+		}
+		if !strings.Contains(result, "Total replacements made: 3") {
+			t.Errorf("find_replace_all(%+v) = %q, want 3 replacements", params, result)
+		}
+		if got, want := readTempFile(t, "mixed.go"), "baz := baz + baz\n"; got != want {
+			t.Errorf("mixed.go = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestFindReplaceAllLiteralReplacement(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeTree(t, map[string]string{"prices.txt": "cost: 5\n"})
+
+	input, _ := json.Marshal(FindReplaceAllParams{Find: "5", Replace: "$1.00", CaseSensitive: true})
+	if _, err := FindReplaceAllDefinition.Function(context.Background(), input); err != nil {
+		t.Fatalf("find_replace_all error = %v", err)
+	}
+	if got, want := readTempFile(t, "prices.txt"), "cost: $1.00\n"; got != want {
+		t.Errorf("prices.txt = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+var GetMergeMessageDefinition = ToolDefinition{
+	Name:        "get_merge_message",
+	Description: "Read the merge commit message git prepared in .git/MERGE_MSG (e.g. \"Merge branch 'feature' into main\"). Pass use_merge_message to git_save_changes to commit with this message instead of a generic one.",
+	InputSchema: GetMergeMessageInputSchema,
+	Function:    GetMergeMessage,
+}
+
+type GetMergeMessageInput struct {
+	// No parameters needed for this tool
+}
+
+var GetMergeMessageInputSchema = GenerateSchema[GetMergeMessageInput]()
+
+func GetMergeMessage(ctx context.Context, input json.RawMessage) (string, error) {
+	message, err := ReadMergeMessage(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get merge message: %w", err)
+	}
+
+	if message == "" {
+		return "No prepared merge message found (.git/MERGE_MSG is missing or empty); git_save_changes will use a GitSynth message", nil
+	}
+
+	return fmt.Sprintf("Prepared merge message:\n\n%s", message), nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGetMergeMessage(t *testing.T) {
+	initTestRepo(t)
+	mergeWithConflicts(t,
+		map[string]string{"f.txt": "base\n"},
+		map[string]string{"f.txt": "ours\n"},
+		map[string]string{"f.txt": "theirs\n"},
+	)
+
+	got, err := GetMergeMessage(context.Background(), json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("GetMergeMessage() error = %v", err)
+	}
+	if !strings.Contains(got, "Merge branch 'feature'") {
+		t.Errorf("GetMergeMessage() = %q, want git's prepared merge message", got)
+	}
+	// git lists the conflicted files in comment lines, which are stripped
+	if strings.Contains(got, "# Conflicts") {
+		t.Errorf("GetMergeMessage() = %q, want comment lines stripped", got)
+	}
+}
+
+func TestGetMergeMessageWithoutMerge(t *testing.T) {
+	initTestRepo(t)
+	commitFiles(t, "base", map[string]string{"f.txt": "base\n"})
+
+	got, err := GetMergeMessage(context.Background(), json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("GetMergeMessage() error = %v", err)
+	}
+	if !strings.HasPrefix(got, "No prepared merge message found") {
+		t.Errorf("GetMergeMessage() = %q, want it to report no merge message", got)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -13,12 +14,13 @@ var GitSaveChangesDefinition = ToolDefinition{
 }
 
 type GitSaveChangesInput struct {
-	Message string `json:"message" jsonschema_description:"The commit message (will be prefixed with [GitSynth])"`
+	Message         string `json:"message" jsonschema_description:"The commit message (will be prefixed with [GitSynth])"`
+	UseMergeMessage bool   `json:"use_merge_message,omitempty" jsonschema_description:"If true, use git's prepared merge message (.git/MERGE_MSG) as the subject, with the provided message as the body. Falls back to the provided message when there is none."`
 }
 
 var GitSaveChangesInputSchema = GenerateSchema[GitSaveChangesInput]()
 
-func GitSaveChanges(input json.RawMessage) (string, error) {
+func GitSaveChanges(ctx context.Context, input json.RawMessage) (string, error) {
 	var params GitSaveChangesInput
 	if err := json.Unmarshal(input, &params); err != nil {
 		return "", fmt.Errorf("failed to parse parameters: %w", err)
@@ -30,11 +32,19 @@ func GitSaveChanges(input json.RawMessage) (string, error) {
 	}
 
 	// Save changes
-	result, err := SaveChanges(params.Message)
+	save := SaveChanges
+	if params.UseMergeMessage {
+		save = SaveMergeChanges
+	}
+	result, err := save(ctx, params.Message)
 	if err != nil {
 		return "", fmt.Errorf("failed to save changes: %w", err)
 	}
 
-	return fmt.Sprintf("Changes committed successfully with message: [GitSynth] %s\n\n%s", 
+	if params.UseMergeMessage {
+		return fmt.Sprintf("Changes committed successfully using the prepared merge message\n\n%s", result), nil
+	}
+
+	return fmt.Sprintf("Changes committed successfully with message: [GitSynth] %s\n\n%s",
 		params.Message, result), nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGitSaveChangesUsesMergeMessage(t *testing.T) {
+	initTestRepo(t)
+	mergeWithConflicts(t,
+		map[string]string{"f.txt": "base\n"},
+		map[string]string{"f.txt": "ours\n"},
+		map[string]string{"f.txt": "theirs\n"},
+	)
+	writeTree(t, map[string]string{"f.txt": "resolved\n"})
+
+	input, _ := json.Marshal(GitSaveChangesInput{Message: "resolve f.txt", UseMergeMessage: true})
+	if _, err := GitSaveChanges(context.Background(), input); err != nil {
+		t.Fatalf("GitSaveChanges() error = %v", err)
+	}
+
+	if got, want := runGit(t, "log", "-1", "--format=%s"), "Merge branch 'feature'"; got != want {
+		t.Errorf("commit subject = %q, want %q", got, want)
+	}
+	if got, want := runGit(t, "log", "-1", "--format=%b"), "[GitSynth] resolve f.txt"; got != want {
+		t.Errorf("commit body = %q, want %q", got, want)
+	}
+	if parents := runGit(t, "log", "-1", "--format=%p"); len(strings.Fields(parents)) != 2 {
+		t.Errorf("commit parents = %q, want a merge commit", parents)
+	}
+}
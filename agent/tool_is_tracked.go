@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// When enabled, tools refuse to modify files that are not tracked by git instead of warning
+var strictTracking = false
+
+var IsTrackedDefinition = ToolDefinition{
+	Name:        "is_tracked",
+	Description: "Check whether a file is tracked by git, untracked, or ignored. Untracked and ignored files are rarely part of conflict resolution and usually should not be edited or deleted.",
+	InputSchema: IsTrackedInputSchema,
+	Function:    IsTracked,
+}
+
+type IsTrackedInput struct {
+	Path string `json:"path" jsonschema_description:"The path to the file to check"`
+}
+
+var IsTrackedInputSchema = GenerateSchema[IsTrackedInput]()
+
+func IsTracked(ctx context.Context, input json.RawMessage) (string, error) {
+	var params IsTrackedInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if params.Path == "" {
+		return "", fmt.Errorf("file path cannot be empty")
+	}
+
+	status, err := GetTrackingStatus(ctx, params.Path)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("File %s is %s", params.Path, status), nil
+}
+
+// checkTrackedForEdit verifies that a file about to be modified is tracked by git and not protected.
+// Returns a warning to append to the tool result, or an error if strict tracking is enabled.
+func checkTrackedForEdit(ctx context.Context, path string) (string, error) {
+	if err := checkProtected(path); err != nil {
+		return "", err
+	}
+
+	status, err := GetTrackingStatus(ctx, path)
+	if err != nil || status == TrackingStatusTracked {
+		return "", nil
+	}
+
+	if strictTracking {
+		return "", fmt.Errorf("refusing to modify %s file %s (strict tracking is enabled)", status, path)
+	}
+	return fmt.Sprintf("\n\nWarning: %s is %s by git, so it is probably not part of the conflict resolution.", path, status), nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGetTrackingStatus(t *testing.T) {
+	initTestRepo(t)
+	commitFiles(t, "initial", map[string]string{"tracked.txt": "a\n", ".gitignore": "*.log\n"})
+	if err := os.WriteFile("untracked.txt", []byte("b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("debug.log", []byte("c\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"tracked.txt", TrackingStatusTracked},
+		{"untracked.txt", TrackingStatusUntracked},
+		{"debug.log", TrackingStatusIgnored},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := GetTrackingStatus(context.Background(), tt.path)
+			if err != nil {
+				t.Fatalf("GetTrackingStatus() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetTrackingStatus() = %q, want %q", got, tt.want)
+			}
+
+			input, _ := json.Marshal(IsTrackedInput{Path: tt.path})
+			result, err := IsTracked(context.Background(), input)
+			if err != nil || !strings.HasSuffix(result, tt.want) {
+				t.Errorf("IsTracked() = %q, %v, want it to report %q", result, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckTrackedForEdit(t *testing.T) {
+	initTestRepo(t)
+	commitFiles(t, "initial", map[string]string{"tracked.txt": "a\n"})
+	if err := os.WriteFile("untracked.txt", []byte("b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { strictTracking = false })
+
+	strictTracking = false
+	if warning, err := checkTrackedForEdit(context.Background(), "tracked.txt"); warning != "" || err != nil {
+		t.Errorf("checkTrackedForEdit(tracked) = %q, %v, want no warning", warning, err)
+	}
+	if warning, err := checkTrackedForEdit(context.Background(), "untracked.txt"); !strings.Contains(warning, "Warning") || err != nil {
+		t.Errorf("checkTrackedForEdit(untracked) = %q, %v, want a warning", warning, err)
+	}
+
+	strictTracking = true
+	if _, err := checkTrackedForEdit(context.Background(), "tracked.txt"); err != nil {
+		t.Errorf("checkTrackedForEdit(tracked) error = %v under strict tracking", err)
+	}
+	if _, err := checkTrackedForEdit(context.Background(), "untracked.txt"); err == nil {
+		t.Error("checkTrackedForEdit(untracked) succeeded under strict tracking, want an error")
+	}
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,22 +12,27 @@ import (
 
 var ListFilesDefinition = ToolDefinition{
 	Name:        "list_files",
-	Description: "List files and directories at a given path. If no path is provided, lists files in the current directory.",
+	Description: "List files and directories at a given path. If no path is provided, lists files in the current directory. Set recursive to walk subdirectories, optionally limited by max_depth. Set only_conflicts to list only files containing merge conflict markers. Directories end with a slash and ignored files are skipped.",
 	InputSchema: ListFilesInputSchema,
 	Function:    ListFiles,
 }
 
 type ListFilesInput struct {
-	Path string `json:"path,omitempty" jsonschema_description:"Optional relative path to list files from. Defaults to current directory if not provided."`
+	Path          string `json:"path,omitempty" jsonschema_description:"Optional relative path to list files from. Defaults to current directory if not provided."`
+	Recursive     bool   `json:"recursive,omitempty" jsonschema_description:"Optional. If true, also lists the contents of subdirectories."`
+	MaxDepth      int    `json:"max_depth,omitempty" jsonschema_description:"Optional. With recursive, how many directory levels to descend. 1 lists only the given directory. Defaults to no limit."`
+	OnlyConflicts bool   `json:"only_conflicts,omitempty" jsonschema_description:"Optional. If true, lists only files containing merge conflict markers and omits directories."`
 }
 
 var ListFilesInputSchema = GenerateSchema[ListFilesInput]()
 
-func ListFiles(input json.RawMessage) (string, error) {
+// maxListedEntries caps how many entries a single list_files call returns
+const maxListedEntries = 1000
+
+func ListFiles(ctx context.Context, input json.RawMessage) (string, error) {
 	listFilesInput := ListFilesInput{}
-	err := json.Unmarshal(input, &listFilesInput)
-	if err != nil {
-		panic(err)
+	if err := json.Unmarshal(input, &listFilesInput); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
 	}
 
 	dir := "."
@@ -33,39 +40,87 @@ func ListFiles(input json.RawMessage) (string, error) {
 		dir = listFilesInput.Path
 	}
 
-	// Load .gitignore patterns if available
+	maxDepth := 1
+	if listFilesInput.Recursive {
+		maxDepth = listFilesInput.MaxDepth
+	}
+
 	ignorePatterns := loadGitignorePatterns()
+	files := []string{}
+	truncated, err := listDirectory(ctx, dir, "", 1, maxDepth, listFilesInput.OnlyConflicts, ignorePatterns, &files)
+	if err != nil {
+		return "", err
+	}
+	if truncated {
+		files = append(files, fmt.Sprintf("... (truncated after %d entries, narrow the path or lower max_depth)", maxListedEntries))
+	}
 
-	// Read directory entries (non-recursively)
-	entries, err := os.ReadDir(dir)
+	result, err := json.Marshal(files)
 	if err != nil {
 		return "", err
 	}
 
-	var files []string
+	return string(result), nil
+}
+
+// listDirectory appends the entries of dir to files as paths relative to the listing root,
+// descending into subdirectories while depth < maxDepth (maxDepth <= 0 means no limit).
+// With onlyConflicts, directories are omitted and only files with conflict markers are kept.
+// Returns true once maxListedEntries is reached.
+func listDirectory(ctx context.Context, dir string, relDir string, depth int, maxDepth int, onlyConflicts bool, ignorePatterns []string, files *[]string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+
+	// Ask git which entries it ignores, falling back to the top-level .gitignore outside a repository
+	var paths []string
+	for _, entry := range entries {
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	ignored, gitErr := gitIgnoredPaths(ctx, paths)
+
 	for _, entry := range entries {
 		name := entry.Name()
 		path := filepath.Join(dir, name)
-		relPath, _ := filepath.Rel(dir, path)
+		relPath := filepath.Join(relDir, name)
 
-		// Skip if matches gitignore patterns
-		if shouldIgnore(relPath, entry.IsDir(), ignorePatterns) {
+		// Skip ignored files
+		if gitErr == nil && ignored[path] {
+			continue
+		}
+		if gitErr != nil && shouldIgnore(relPath, entry.IsDir(), ignorePatterns) {
 			continue
 		}
 
-		if entry.IsDir() {
-			files = append(files, relPath+"/")
-		} else {
-			files = append(files, relPath)
+		if len(*files) >= maxListedEntries {
+			return true, nil
 		}
-	}
 
-	result, err := json.Marshal(files)
-	if err != nil {
-		return "", err
+		if !entry.IsDir() {
+			if onlyConflicts {
+				if hasConflicts, err := HasMergeConflicts(path); err != nil || !hasConflicts {
+					continue
+				}
+			}
+			*files = append(*files, relPath)
+			continue
+		}
+		if !onlyConflicts {
+			*files = append(*files, relPath+"/")
+		}
+
+		// Never descend into the git directory
+		if name == ".git" || (maxDepth > 0 && depth >= maxDepth) {
+			continue
+		}
+		truncated, err := listDirectory(ctx, path, relPath, depth+1, maxDepth, onlyConflicts, ignorePatterns, files)
+		if err != nil || truncated {
+			return truncated, err
+		}
 	}
 
-	return string(result), nil
+	return false, nil
 }
 
 // loadGitignorePatterns loads patterns from the .gitignore file if it exists
@@ -98,7 +153,7 @@ func loadGitignorePatterns() []string {
 func shouldIgnore(path string, isDir bool, patterns []string) bool {
 	// Convert Windows path separators to Unix style for matching
 	path = filepath.ToSlash(path)
-	
+
 	// Always check the file/dir name itself
 	name := filepath.Base(path)
 
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+var MergeDiffstatDefinition = ToolDefinition{
+	Name:        "merge_diffstat",
+	Description: "Show a diffstat of the merge in progress: which files the incoming branch (MERGE_HEAD) changed since it diverged from the current branch, and how much. Gives a high-level map of the merge before diving into individual conflicts.",
+	InputSchema: MergeDiffstatInputSchema,
+	Function:    MergeDiffstat,
+}
+
+type MergeDiffstatInput struct {
+	// No parameters needed for this tool
+}
+
+var MergeDiffstatInputSchema = GenerateSchema[MergeDiffstatInput]()
+
+func MergeDiffstat(ctx context.Context, input json.RawMessage) (string, error) {
+	if !IsMergeInProgress(ctx) {
+		return "No merge is in progress (MERGE_HEAD does not exist)", nil
+	}
+
+	// The three-dot form diffs MERGE_HEAD against the merge base, i.e. only the incoming side's changes
+	stat, err := ExecuteGitCommand(ctx, "diff", "--stat", "HEAD...MERGE_HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to get merge diffstat: %w", err)
+	}
+
+	if stat == "" {
+		return "The incoming branch has no changes relative to the merge base", nil
+	}
+
+	return fmt.Sprintf("Changes introduced by the incoming branch (HEAD...MERGE_HEAD):\n\n%s", stat), nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMergeDiffstat(t *testing.T) {
+	initTestRepo(t)
+	mergeWithConflicts(t,
+		map[string]string{"shared.txt": "base\n", "ours_only.txt": "base\n"},
+		map[string]string{"shared.txt": "ours\n", "ours_only.txt": "changed on main\n"},
+		map[string]string{"shared.txt": "theirs\n", "added.txt": "new\nfile\n"},
+	)
+
+	got, err := MergeDiffstat(context.Background(), json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("MergeDiffstat() error = %v", err)
+	}
+	for _, want := range []string{"HEAD...MERGE_HEAD", "shared.txt", "added.txt", "2 files changed"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("MergeDiffstat() = %q, want it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "ours_only.txt") {
+		t.Errorf("MergeDiffstat() = %q, want only the incoming branch's changes", got)
+	}
+}
+
+func TestMergeDiffstatWithoutMerge(t *testing.T) {
+	initTestRepo(t)
+	commitFiles(t, "base", map[string]string{"f.txt": "base\n"})
+
+	got, err := MergeDiffstat(context.Background(), json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("MergeDiffstat() error = %v", err)
+	}
+	if !strings.HasPrefix(got, "No merge is in progress") {
+		t.Errorf("MergeDiffstat() = %q, want it to report that no merge is in progress", got)
+	}
+}
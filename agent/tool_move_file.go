@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var MoveFileDefinition = ToolDefinition{
+	Name:        "move_file",
+	Description: "Move or rename a file with 'git mv', creating destination directories as needed. Untracked files are renamed and then staged. Use this to resolve rename/rename and rename/delete conflicts instead of leaving duplicate copies.",
+	InputSchema: MoveFileInputSchema,
+	Function:    MoveFile,
+}
+
+type MoveFileInput struct {
+	Source      string `json:"source" jsonschema_description:"The current path of the file"`
+	Destination string `json:"destination" jsonschema_description:"The new path of the file, which must not exist yet"`
+}
+
+var MoveFileInputSchema = GenerateSchema[MoveFileInput]()
+
+func MoveFile(ctx context.Context, input json.RawMessage) (string, error) {
+	var params MoveFileInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if params.Source == "" || params.Destination == "" {
+		return "", fmt.Errorf("source and destination cannot be empty")
+	}
+	if err := ValidateFileExists(params.Source); err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(params.Destination); err == nil {
+		return "", fmt.Errorf("destination already exists: %s", params.Destination)
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to access destination: %w", err)
+	}
+	for _, path := range []string{params.Source, params.Destination} {
+		if err := checkProtected(path); err != nil {
+			return "", err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(params.Destination), 0755); err != nil {
+		return "", fmt.Errorf("failed to create destination directories: %w", err)
+	}
+
+	snapshotFile(params.Source)
+	snapshotFile(params.Destination)
+
+	status, err := GetTrackingStatus(ctx, params.Source)
+	if err != nil {
+		return "", err
+	}
+	if status == TrackingStatusTracked {
+		if _, err := ExecuteGitCommand(ctx, "mv", "--", params.Source, params.Destination); err != nil {
+			return "", fmt.Errorf("failed to move %s: %w", params.Source, err)
+		}
+		return fmt.Sprintf("Moved %s to %s with git mv", params.Source, params.Destination) + recordEdit(params.Destination), nil
+	}
+
+	// git mv refuses files it does not track, so rename on disk and stage the result
+	if err := os.Rename(params.Source, params.Destination); err != nil {
+		return "", fmt.Errorf("failed to move %s: %w", params.Source, err)
+	}
+	if _, err := ExecuteGitCommand(ctx, "add", "--", params.Destination); err != nil {
+		return fmt.Sprintf("Moved %s to %s, but failed to stage it: %v", params.Source, params.Destination, err), nil
+	}
+	return fmt.Sprintf("Moved %s (%s) to %s and staged it", params.Source, status, params.Destination) + recordEdit(params.Destination), nil
+}
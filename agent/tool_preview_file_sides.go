@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+var PreviewFileSidesDefinition = ToolDefinition{
+	Name:        "preview_file_sides",
+	Description: "Preview the full contents a conflicted file would have if resolved entirely with 'ours' (the current branch, index stage 2) or entirely with 'theirs' (the incoming branch, index stage 3). Use this before deciding to take one side for a whole file.",
+	InputSchema: PreviewFileSidesInputSchema,
+	Function:    PreviewFileSides,
+}
+
+type PreviewFileSidesInput struct {
+	Path string `json:"path" jsonschema_description:"The path to the conflicted file to preview"`
+}
+
+var PreviewFileSidesInputSchema = GenerateSchema[PreviewFileSidesInput]()
+
+func PreviewFileSides(ctx context.Context, input json.RawMessage) (string, error) {
+	var params PreviewFileSidesInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if params.Path == "" {
+		return "", fmt.Errorf("file path cannot be empty")
+	}
+
+	ours, oursErr := GetFileVersionAtStage(ctx, params.Path, 2)
+	theirs, theirsErr := GetFileVersionAtStage(ctx, params.Path, 3)
+	if oursErr != nil && theirsErr != nil {
+		return "", fmt.Errorf("no conflict stages found for %s, is the file currently conflicted?", params.Path)
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("File: %s\n\n", params.Path))
+
+	result.WriteString("Taking ours (stage 2):\n")
+	if oursErr != nil {
+		result.WriteString("(file does not exist on our side, taking ours would delete it)\n\n")
+	} else {
+		result.WriteString(fmt.Sprintf("```\n%s\n```\n\n", ours))
+	}
+
+	result.WriteString("Taking theirs (stage 3):\n")
+	if theirsErr != nil {
+		result.WriteString("(file does not exist on their side, taking theirs would delete it)\n")
+	} else {
+		result.WriteString(fmt.Sprintf("```\n%s\n```\n", theirs))
+	}
+
+	return result.String(), nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPreviewFileSides(t *testing.T) {
+	initTestRepo(t)
+	mergeWithConflicts(t,
+		map[string]string{"config.txt": "port = 80\n"},
+		map[string]string{"config.txt": "port = 8080\n"},
+		map[string]string{"config.txt": "port = 9090\n"},
+	)
+
+	input, _ := json.Marshal(PreviewFileSidesInput{Path: "config.txt"})
+	got, err := PreviewFileSides(context.Background(), input)
+	if err != nil {
+		t.Fatalf("PreviewFileSides() error = %v", err)
+	}
+	for _, want := range []string{
+		"Taking ours (stage 2):\n```\nport = 8080\n```",
+		"Taking theirs (stage 3):\n```\nport = 9090\n```",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("PreviewFileSides() = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestPreviewFileSidesRejectsUnconflictedFile(t *testing.T) {
+	initTestRepo(t)
+	commitFiles(t, "add file", map[string]string{"clean.txt": "clean\n"})
+
+	input, _ := json.Marshal(PreviewFileSidesInput{Path: "clean.txt"})
+	if _, err := PreviewFileSides(context.Background(), input); err == nil {
+		t.Error("PreviewFileSides(clean.txt) succeeded, want an error for a file without conflict stages")
+	}
+}
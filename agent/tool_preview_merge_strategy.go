@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var PreviewMergeStrategyDefinition = ToolDefinition{
+	Name:        "preview_merge_strategy",
+	Description: "Preview what a conflicted file would look like if git re-merged it with a strategy option: 'ours' (-X ours, conflicting hunks favor our side), 'theirs' (-X theirs), or 'union' (keep both sides of each conflict). Non-conflicting changes from both sides are still merged. The real working tree is not touched.",
+	InputSchema: PreviewMergeStrategyInputSchema,
+	Function:    PreviewMergeStrategy,
+}
+
+type PreviewMergeStrategyInput struct {
+	Path     string `json:"path" jsonschema_description:"The path to the conflicted file to preview"`
+	Strategy string `json:"strategy" jsonschema_description:"The strategy option to preview: 'ours', 'theirs', or 'union'"`
+}
+
+var PreviewMergeStrategyInputSchema = GenerateSchema[PreviewMergeStrategyInput]()
+
+func PreviewMergeStrategy(ctx context.Context, input json.RawMessage) (string, error) {
+	var params PreviewMergeStrategyInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if params.Path == "" {
+		return "", fmt.Errorf("file path cannot be empty")
+	}
+	if params.Strategy != "ours" && params.Strategy != "theirs" && params.Strategy != "union" {
+		return "", fmt.Errorf("strategy must be 'ours', 'theirs', or 'union'")
+	}
+
+	merged, err := MergeWithStrategy(ctx, params.Path, params.Strategy)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("File: %s\nStrategy: %s\n\nMerged contents:\n```\n%s```", params.Path, params.Strategy, merged), nil
+}
+
+// MergeWithStrategy re-merges the index stages of a conflicted file in a scratch directory
+// using git merge-file with the given strategy (ours, theirs, or union) and returns the result
+func MergeWithStrategy(ctx context.Context, path, strategy string) (string, error) {
+	ours, oursErr := GetFileVersionAtStage(ctx, path, 2)
+	theirs, theirsErr := GetFileVersionAtStage(ctx, path, 3)
+	if oursErr != nil || theirsErr != nil {
+		return "", fmt.Errorf("%s does not have both sides in the index, so it cannot be re-merged", path)
+	}
+	// The ancestor is missing when both sides added the file
+	ancestor, _ := GetFileVersionAtStage(ctx, path, 1)
+
+	scratch, err := os.MkdirTemp("", "gitsynth-merge-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+
+	files := []struct{ name, content string }{{"ours", ours}, {"base", ancestor}, {"theirs", theirs}}
+	var args []string
+	for _, file := range files {
+		filePath := filepath.Join(scratch, file.name)
+		if err := os.WriteFile(filePath, []byte(withTrailingNewline(file.content)), 0644); err != nil {
+			return "", fmt.Errorf("failed to write scratch file: %w", err)
+		}
+		args = append(args, filePath)
+	}
+
+	cmd := toolCommand(ctx, "git", append([]string{"merge-file", "-p", "--" + strategy}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git merge-file failed: %s\nStderr: %s", err, stderr.String())
+	}
+
+	return stdout.String(), nil
+}
+
+// withTrailingNewline restores the final newline that git command output loses to trimming
+func withTrailingNewline(content string) string {
+	if content == "" || content[len(content)-1] == '\n' {
+		return content
+	}
+	return content + "\n"
+}
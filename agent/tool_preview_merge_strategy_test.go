@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMergeWithStrategy(t *testing.T) {
+	initTestRepo(t)
+	// Both sides change line 2; only ours changes line 5 and only theirs changes line 8
+	mergeWithConflicts(t,
+		map[string]string{"f.txt": "1\n2\n3\n4\n5\n6\n7\n8\n"},
+		map[string]string{"f.txt": "1\n2 ours\n3\n4\n5 ours\n6\n7\n8\n"},
+		map[string]string{"f.txt": "1\n2 theirs\n3\n4\n5\n6\n7\n8 theirs\n"},
+	)
+	conflicted := readTempFile(t, "f.txt")
+
+	tests := []struct {
+		strategy string
+		want     string
+	}{
+		{"ours", "1\n2 ours\n3\n4\n5 ours\n6\n7\n8 theirs\n"},
+		{"theirs", "1\n2 theirs\n3\n4\n5 ours\n6\n7\n8 theirs\n"},
+		{"union", "1\n2 ours\n2 theirs\n3\n4\n5 ours\n6\n7\n8 theirs\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			got, err := MergeWithStrategy(context.Background(), "f.txt", tt.strategy)
+			if err != nil {
+				t.Fatalf("MergeWithStrategy() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("MergeWithStrategy() = %q, want %q", got, tt.want)
+			}
+
+			input, _ := json.Marshal(PreviewMergeStrategyInput{Path: "f.txt", Strategy: tt.strategy})
+			preview, err := PreviewMergeStrategy(context.Background(), input)
+			if err != nil || !strings.Contains(preview, tt.want) {
+				t.Errorf("PreviewMergeStrategy() = %q, %v, want it to show the merged contents", preview, err)
+			}
+		})
+	}
+
+	if got := readTempFile(t, "f.txt"); got != conflicted {
+		t.Errorf("working tree file changed to %q, want it untouched", got)
+	}
+}
+
+func TestPreviewMergeStrategyInvalid(t *testing.T) {
+	input, _ := json.Marshal(PreviewMergeStrategyInput{Path: "f.txt", Strategy: "recursive"})
+	if _, err := PreviewMergeStrategy(context.Background(), input); err == nil {
+		t.Error("PreviewMergeStrategy() succeeded with an unknown strategy, want an error")
+	}
+}
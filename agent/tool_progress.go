@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+var ProgressDefinition = ToolDefinition{
+	Name:        "progress",
+	Description: "Show overall progress: how many of the files that were conflicted when GitSynth started no longer contain conflict markers, and which ones remain.",
+	InputSchema: ProgressInputSchema,
+	Function:    Progress,
+}
+
+type ProgressInput struct {
+	// No parameters needed for this tool
+}
+
+var ProgressInputSchema = GenerateSchema[ProgressInput]()
+
+func Progress(ctx context.Context, input json.RawMessage) (string, error) {
+	resolved, total := MergeProgress()
+	if total == 0 {
+		return "No files were conflicted when GitSynth started", nil
+	}
+
+	result := fmt.Sprintf("Resolved %d of %d conflicted files %s", resolved, total, progressBar(resolved, total))
+	if resolved < total {
+		conflictedAtStartMu.Lock()
+		files := append([]string(nil), conflictedAtStart...)
+		conflictedAtStartMu.Unlock()
+
+		result += "\n\nStill containing conflict markers:"
+		for _, file := range files {
+			if hasConflicts, err := HasMergeConflicts(file); err == nil && hasConflicts {
+				result += fmt.Sprintf("\n  %s", file)
+			}
+		}
+	}
+	return result, nil
+}
+
+// Width of the text progress bar in characters
+const progressBarWidth = 20
+
+// progressBar renders resolved/total as a fixed-width text bar, e.g. [#####---------------]
+func progressBar(resolved, total int) string {
+	filled := 0
+	if total > 0 {
+		filled = resolved * progressBarWidth / total
+	}
+	bar := make([]byte, progressBarWidth)
+	for i := range bar {
+		if i < filled {
+			bar[i] = '#'
+		} else {
+			bar[i] = '-'
+		}
+	}
+	return "[" + string(bar) + "]"
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestProgressReflectsClearedFiles(t *testing.T) {
+	initTestRepo(t)
+	mergeWithConflicts(t,
+		map[string]string{"a.txt": "base\n", "b.txt": "base\n", "c.txt": "base\n"},
+		map[string]string{"a.txt": "ours\n", "b.txt": "ours\n", "c.txt": "ours\n"},
+		map[string]string{"a.txt": "theirs\n", "b.txt": "theirs\n", "c.txt": "theirs\n"},
+	)
+	if err := snapshotProgress(context.Background()); err != nil {
+		t.Fatalf("snapshotProgress() error = %v", err)
+	}
+	t.Cleanup(func() { conflictedAtStart = nil })
+
+	progress := func() string {
+		t.Helper()
+		got, err := Progress(context.Background(), json.RawMessage(`{}`))
+		if err != nil {
+			t.Fatalf("Progress() error = %v", err)
+		}
+		return got
+	}
+
+	if got := progress(); !strings.HasPrefix(got, "Resolved 0 of 3 conflicted files [----") {
+		t.Errorf("Progress() = %q, want nothing resolved yet", got)
+	}
+
+	// Clearing the markers counts a file as resolved even before it is staged
+	writeTree(t, map[string]string{"a.txt": "resolved\n"})
+	got := progress()
+	if !strings.HasPrefix(got, "Resolved 1 of 3") || strings.Contains(got, "\n  a.txt") || !strings.Contains(got, "\n  b.txt\n  c.txt") {
+		t.Errorf("Progress() = %q, want a.txt resolved and b.txt, c.txt remaining", got)
+	}
+
+	// Deleting a file resolves it too
+	if err := os.Remove("b.txt"); err != nil {
+		t.Fatal(err)
+	}
+	writeTree(t, map[string]string{"c.txt": "resolved\n"})
+	if got := progress(); got != "Resolved 3 of 3 conflicted files [####################]" {
+		t.Errorf("Progress() = %q, want every file resolved", got)
+	}
+}
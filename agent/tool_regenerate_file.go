@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Mapping of file name glob patterns to the shell command that regenerates matching files
+var regenerators = map[string]string{}
+
+var RegenerateFileDefinition = ToolDefinition{
+	Name:        "regenerate_file",
+	Description: "Resolve a conflicted generated file (e.g. a lockfile or generated client) by running its configured regeneration command instead of merging the conflict markers. Only works for files matching a pattern in the 'regenerators' section of the GitSynth config.",
+	InputSchema: RegenerateFileInputSchema,
+	Function:    RegenerateFile,
+}
+
+type RegenerateFileInput struct {
+	Path string `json:"path" jsonschema_description:"The path to the generated file to regenerate"`
+}
+
+var RegenerateFileInputSchema = GenerateSchema[RegenerateFileInput]()
+
+func RegenerateFile(ctx context.Context, input json.RawMessage) (string, error) {
+	var params RegenerateFileInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if params.Path == "" {
+		return "", fmt.Errorf("file path cannot be empty")
+	}
+
+	return regenerateFile(ctx, params.Path)
+}
+
+// regenerateFile runs the regeneration command configured for a path and reports whether
+// the regenerated file is free of conflict markers, staging it if it is
+func regenerateFile(ctx context.Context, path string) (string, error) {
+	command, pattern := findRegenerator(path)
+	if command == "" {
+		return "", fmt.Errorf("no regeneration command is configured for %s", path)
+	}
+	if err := checkProtected(path); err != nil {
+		return "", err
+	}
+
+	snapshotFile(path)
+	cmd := toolCommand(ctx, "sh", "-c", command)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("regeneration command %q failed: %s\nOutput: %s", command, err, output.String())
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Ran %q (configured for '%s')\n", command, pattern))
+	if trimmed := strings.TrimSpace(output.String()); trimmed != "" {
+		result.WriteString(fmt.Sprintf("Output:\n%s\n", trimmed))
+	}
+
+	if err := ValidateFileExists(path); err != nil {
+		result.WriteString(fmt.Sprintf("\nWarning: %s no longer exists after regeneration.", path))
+		return result.String(), nil
+	}
+
+	hasConflicts, err := HasMergeConflicts(path)
+	if err != nil {
+		return "", err
+	}
+	if hasConflicts {
+		result.WriteString(fmt.Sprintf("\nWarning: %s still contains conflict markers after regeneration.", path))
+		return result.String(), nil
+	}
+
+	// Stage the regenerated file so git no longer lists it as unmerged
+	if _, err := ExecuteGitCommand(ctx, "add", "--", path); err != nil {
+		return "", fmt.Errorf("failed to stage %s: %w", path, err)
+	}
+	result.WriteString(fmt.Sprintf("\n%s was regenerated, no longer contains conflict markers and has been staged.", path))
+
+	return result.String(), nil
+}
+
+// findRegenerator returns the command and pattern configured for a path
+func findRegenerator(path string) (string, string) {
+	return matchPatternMap(regenerators, path)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRegenerateFileStagesResult(t *testing.T) {
+	initTestRepo(t)
+	mergeWithConflicts(t,
+		map[string]string{"deps.lock": "a 1\n"},
+		map[string]string{"deps.lock": "a 2\n"},
+		map[string]string{"deps.lock": "a 3\n"},
+	)
+	// A fake generator script that rewrites the lockfile from scratch
+	writeTree(t, map[string]string{"gen.sh": "printf 'a 4\\n' > deps.lock\n"})
+	regenerators = map[string]string{"*.lock": "sh gen.sh"}
+	t.Cleanup(func() { regenerators = map[string]string{} })
+
+	input, _ := json.Marshal(RegenerateFileInput{Path: "deps.lock"})
+	got, err := RegenerateFile(context.Background(), input)
+	if err != nil {
+		t.Fatalf("RegenerateFile() error = %v", err)
+	}
+	if !strings.Contains(got, "has been staged") {
+		t.Errorf("RegenerateFile() = %q, want it to report the file staged", got)
+	}
+	if got := readTempFile(t, "deps.lock"); got != "a 4\n" {
+		t.Errorf("deps.lock = %q, want the regenerated content", got)
+	}
+
+	unmerged, err := ListUnmergedFiles(context.Background())
+	if err != nil {
+		t.Fatalf("ListUnmergedFiles() error = %v", err)
+	}
+	if len(unmerged) != 0 {
+		t.Errorf("unmerged files = %v, want deps.lock staged", unmerged)
+	}
+	if staged := runGit(t, "show", ":deps.lock"); staged != "a 4" {
+		t.Errorf("staged deps.lock = %q, want the regenerated content", staged)
+	}
+}
+
+func TestRegenerateFileLeavesConflictsUnstaged(t *testing.T) {
+	initTestRepo(t)
+	mergeWithConflicts(t,
+		map[string]string{"deps.lock": "a 1\n"},
+		map[string]string{"deps.lock": "a 2\n"},
+		map[string]string{"deps.lock": "a 3\n"},
+	)
+	regenerators = map[string]string{"*.lock": "true"}
+	t.Cleanup(func() { regenerators = map[string]string{} })
+
+	input, _ := json.Marshal(RegenerateFileInput{Path: "deps.lock"})
+	got, err := RegenerateFile(context.Background(), input)
+	if err != nil {
+		t.Fatalf("RegenerateFile() error = %v", err)
+	}
+	if !strings.Contains(got, "still contains conflict markers") {
+		t.Errorf("RegenerateFile() = %q, want the conflict marker warning", got)
+	}
+	if unmerged, _ := ListUnmergedFiles(context.Background()); len(unmerged) != 1 {
+		t.Errorf("unmerged files = %v, want deps.lock still unmerged", unmerged)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Number of recent commits and languages included in the summary
+const (
+	summaryCommitCount   = 10
+	summaryLanguageCount = 5
+)
+
+// Languages recognized by file extension
+var extensionLanguages = map[string]string{
+	".go": "Go", ".js": "JavaScript", ".jsx": "JavaScript", ".mjs": "JavaScript", ".cjs": "JavaScript",
+	".ts": "TypeScript", ".tsx": "TypeScript", ".py": "Python", ".rb": "Ruby", ".rs": "Rust",
+	".java": "Java", ".kt": "Kotlin", ".swift": "Swift", ".c": "C", ".h": "C", ".cc": "C++",
+	".cpp": "C++", ".hpp": "C++", ".cs": "C#", ".php": "PHP", ".scala": "Scala", ".sh": "Shell",
+	".html": "HTML", ".css": "CSS", ".scss": "CSS", ".vue": "Vue", ".svelte": "Svelte",
+}
+
+var RepoSummaryDefinition = ToolDefinition{
+	Name:        "repo_summary",
+	Description: "Get a compact overview of the repository in one call: top-level directories with file counts, the main languages, the README's first paragraph, and recent commit subjects. Use this to get a feel for the repository before resolving conflicts.",
+	InputSchema: RepoSummaryInputSchema,
+	Function:    RepoSummary,
+}
+
+type RepoSummaryInput struct {
+	// No parameters needed for this tool
+}
+
+var RepoSummaryInputSchema = GenerateSchema[RepoSummaryInput]()
+
+func RepoSummary(ctx context.Context, input json.RawMessage) (string, error) {
+	output, err := ExecuteGitCommand(ctx, "ls-files")
+	if err != nil {
+		return "", fmt.Errorf("failed to list repository files: %w", err)
+	}
+
+	var files []string
+	if output != "" {
+		files = strings.Split(output, "\n")
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Repository summary (%d tracked files)\n", len(files)))
+
+	result.WriteString("\nTop-level layout:\n")
+	for _, line := range summarizeLayout(files) {
+		result.WriteString("  " + line + "\n")
+	}
+
+	if languages := summarizeLanguages(files); len(languages) > 0 {
+		result.WriteString(fmt.Sprintf("\nLanguages: %s\n", strings.Join(languages, ", ")))
+	}
+
+	if readme := readmeFirstParagraph(files); readme != "" {
+		result.WriteString(fmt.Sprintf("\nREADME:\n%s\n", readme))
+	}
+
+	commits, err := ExecuteGitCommand(ctx, "log", fmt.Sprintf("--max-count=%d", summaryCommitCount), "--pretty=format:%h %s")
+	if err == nil && commits != "" {
+		result.WriteString("\nRecent commits:\n")
+		for _, line := range strings.Split(commits, "\n") {
+			result.WriteString("  " + line + "\n")
+		}
+	}
+
+	return result.String(), nil
+}
+
+// summarizeLayout lists top-level directories with their file counts, then top-level files
+func summarizeLayout(files []string) []string {
+	dirCounts := make(map[string]int)
+	var rootFiles []string
+	for _, file := range files {
+		if dir, _, found := strings.Cut(file, "/"); found {
+			dirCounts[dir]++
+		} else {
+			rootFiles = append(rootFiles, file)
+		}
+	}
+
+	dirs := make([]string, 0, len(dirCounts))
+	for dir := range dirCounts {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	var lines []string
+	for _, dir := range dirs {
+		lines = append(lines, fmt.Sprintf("%s/ (%d files)", dir, dirCounts[dir]))
+	}
+	sort.Strings(rootFiles)
+	return append(lines, rootFiles...)
+}
+
+// summarizeLanguages returns the most common languages by file count, with percentages
+func summarizeLanguages(files []string) []string {
+	counts := make(map[string]int)
+	total := 0
+	for _, file := range files {
+		if language, ok := extensionLanguages[strings.ToLower(filepath.Ext(file))]; ok {
+			counts[language]++
+			total++
+		}
+	}
+
+	languages := make([]string, 0, len(counts))
+	for language := range counts {
+		languages = append(languages, language)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if counts[languages[i]] != counts[languages[j]] {
+			return counts[languages[i]] > counts[languages[j]]
+		}
+		return languages[i] < languages[j]
+	})
+	if len(languages) > summaryLanguageCount {
+		languages = languages[:summaryLanguageCount]
+	}
+
+	for i, language := range languages {
+		languages[i] = fmt.Sprintf("%s (%d%%)", language, counts[language]*100/total)
+	}
+	return languages
+}
+
+// readmeFirstParagraph returns the first prose paragraph of the top-level README, skipping headings
+func readmeFirstParagraph(files []string) string {
+	for _, file := range files {
+		if strings.Contains(file, "/") || !strings.HasPrefix(strings.ToUpper(file), "README") {
+			continue
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return ""
+		}
+
+		var paragraph []string
+		for _, line := range strings.Split(string(content), "\n") {
+			trimmed := strings.TrimSpace(line)
+			switch {
+			case trimmed == "" && len(paragraph) > 0:
+				return strings.Join(paragraph, " ")
+			case trimmed == "", strings.HasPrefix(trimmed, "#"), strings.HasPrefix(trimmed, "!["), strings.HasPrefix(trimmed, "<"):
+				continue
+			default:
+				paragraph = append(paragraph, trimmed)
+			}
+		}
+		return strings.Join(paragraph, " ")
+	}
+	return ""
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRepoSummary(t *testing.T) {
+	initTestRepo(t)
+	commitFiles(t, "add service", map[string]string{
+		"README.md":         "# Service\n\n![badge](badge.svg)\n\nA small service that\nserves requests.\n\nMore details.\n",
+		"cmd/main.go":       "package main\n",
+		"internal/a.go":     "package internal\n",
+		"internal/b.go":     "package internal\n",
+		"web/app.ts":        "export {}\n",
+		"web/index.html":    "<html></html>\n",
+		"web/style.css":     "body {}\n",
+		"internal/data.bin": "\x00",
+	})
+	commitFiles(t, "update docs", map[string]string{"README.md": "# Service\n\nA small service that\nserves requests.\n"})
+
+	got, err := RepoSummary(context.Background(), json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("RepoSummary() error = %v", err)
+	}
+	for _, want := range []string{
+		"Repository summary (8 tracked files)",
+		"Top-level layout:\n  cmd/ (1 files)\n  internal/ (3 files)\n  web/ (3 files)\n  README.md\n",
+		"Languages: Go (50%), CSS (16%), HTML (16%), TypeScript (16%)",
+		"README:\nA small service that serves requests.\n",
+		"Recent commits:\n",
+		" update docs\n",
+		" add service\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RepoSummary() = %q, want it to contain %q", got, want)
+		}
+	}
+	if strings.Index(got, "update docs") > strings.Index(got, "add service") {
+		t.Errorf("RepoSummary() = %q, want the most recent commit first", got)
+	}
+}
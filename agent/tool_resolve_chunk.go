@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+var ResolveChunkDefinition = ToolDefinition{
+	Name:        "resolve_chunk",
+	Description: "Resolve a conflict chunk by keeping one or both sides without retyping them: 'ours' keeps the HEAD side, 'theirs' keeps the incoming side, 'both' keeps ours followed by theirs, and 'union' keeps both but drops repeated lines. Use edit_file_chunk instead when the sides need to be merged by hand.",
+	InputSchema: ResolveChunkInputSchema,
+	Function:    ResolveChunk,
+}
+
+type ResolveChunkInput struct {
+	Path     string `json:"path" jsonschema_description:"The path to the file containing the conflict chunk"`
+	ChunkID  int    `json:"chunk_id" jsonschema_description:"The ID of the conflict chunk to resolve (zero-indexed from the top of the file)"`
+	Strategy string `json:"strategy" jsonschema_description:"Which side or sides to keep: 'ours', 'theirs', 'both', or 'union'"`
+}
+
+var ResolveChunkInputSchema = GenerateSchema[ResolveChunkInput]()
+
+func ResolveChunk(ctx context.Context, input json.RawMessage) (string, error) {
+	var params ResolveChunkInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if err := ValidateFileExists(params.Path); err != nil {
+		return "", err
+	}
+
+	// Warn about (or refuse) editing files git does not track
+	warning, err := checkTrackedForEdit(ctx, params.Path)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(params.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	chunks, err := FindConflictChunks(string(content))
+	if err != nil {
+		return "", err
+	}
+	if params.ChunkID < 0 || params.ChunkID >= len(chunks) {
+		return "", fmt.Errorf("chunk ID %d is out of range (found %d chunks)", params.ChunkID, len(chunks))
+	}
+	chunk := chunks[params.ChunkID]
+
+	resolution, err := chunkSideContent(chunk, params.Strategy)
+	if err != nil {
+		return "", err
+	}
+
+	if err := ReplaceConflictChunk(params.Path, params.ChunkID, resolution); err != nil {
+		return "", fmt.Errorf("failed to replace conflict chunk: %w", err)
+	}
+
+	// Remind the agent of the chunks still left in this file
+	if len(chunks) > 1 {
+		warning += fmt.Sprintf("\n\nNote: %s still has %d unresolved conflict chunk(s); continue with see_file_chunks.", params.Path, len(chunks)-1)
+	}
+	warning += recordEdit(params.Path)
+
+	return fmt.Sprintf("Resolved conflict chunk %d in file %s by keeping %s%s",
+		params.ChunkID, params.Path, describeStrategy(chunk, params.Strategy), warning), nil
+}
+
+// chunkSideContent builds the resolution of a chunk for a strategy
+func chunkSideContent(chunk ConflictChunk, strategy string) (string, error) {
+	switch strategy {
+	case "ours":
+		return chunk.BaseCode, nil
+	case "theirs":
+		return chunk.IncomingCode, nil
+	case "both":
+		if chunk.BaseCode == "" {
+			return chunk.IncomingCode, nil
+		}
+		if chunk.IncomingCode == "" {
+			return chunk.BaseCode, nil
+		}
+		return chunk.BaseCode + "\n" + chunk.IncomingCode, nil
+	case "union":
+		return UnionLines(nil, chunk.BaseCode, chunk.IncomingCode), nil
+	default:
+		return "", fmt.Errorf("invalid strategy %q: must be ours, theirs, both, or union", strategy)
+	}
+}
+
+// describeStrategy names the side or sides a strategy kept, using the chunk's branch labels
+func describeStrategy(chunk ConflictChunk, strategy string) string {
+	switch strategy {
+	case "ours":
+		return fmt.Sprintf("our side (%s)", chunk.BaseLabel)
+	case "theirs":
+		return fmt.Sprintf("their side (%s)", chunk.IncomingLabel)
+	case "both":
+		return fmt.Sprintf("both sides, %s first", chunk.BaseLabel)
+	default:
+		return "the union of both sides' lines"
+	}
+}
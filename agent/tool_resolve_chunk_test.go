@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestResolveChunk(t *testing.T) {
+	const emptyOurs = "a\n<<<<<<< HEAD\n=======\ntheirs1\n>>>>>>> b\nc\n"
+	const emptyTheirs = "a\n<<<<<<< HEAD\nours1\n=======\n>>>>>>> b\nc\n"
+
+	tests := []struct {
+		name        string
+		content     string
+		strategy    string
+		wantContent string
+	}{
+		{"empty ours, take theirs", emptyOurs, "theirs", "a\ntheirs1\nc\n"},
+		{"empty ours, take ours", emptyOurs, "ours", "a\nc\n"},
+		{"empty ours, take both", emptyOurs, "both", "a\ntheirs1\nc\n"},
+		{"empty theirs, take ours", emptyTheirs, "ours", "a\nours1\nc\n"},
+		{"empty theirs, take theirs", emptyTheirs, "theirs", "a\nc\n"},
+		{"empty theirs, take union", emptyTheirs, "union", "a\nours1\nc\n"},
+		{"both sides, take both", "<<<<<<< HEAD\nx\n=======\ny\n>>>>>>> b\n", "both", "x\ny\n"},
+		{"both sides, take union", "<<<<<<< HEAD\nx\ny\n=======\ny\nz\n>>>>>>> b\n", "union", "x\ny\nz\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTempFile(t, "file.txt", tt.content)
+			input, _ := json.Marshal(ResolveChunkInput{Path: path, ChunkID: 0, Strategy: tt.strategy})
+			if _, err := ResolveChunk(context.Background(), input); err != nil {
+				t.Fatalf("ResolveChunk() error = %v", err)
+			}
+			if got := readTempFile(t, path); got != tt.wantContent {
+				t.Errorf("content = %q, want %q", got, tt.wantContent)
+			}
+		})
+	}
+}
+
+func TestResolveChunkInvalidStrategy(t *testing.T) {
+	path := writeTempFile(t, "file.txt", "<<<<<<< HEAD\nx\n=======\ny\n>>>>>>> b\n")
+	input, _ := json.Marshal(ResolveChunkInput{Path: path, ChunkID: 0, Strategy: "mine"})
+	if _, err := ResolveChunk(context.Background(), input); err == nil {
+		t.Error("ResolveChunk() with an invalid strategy succeeded")
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Number of leading lines searched for a generated-code header
+const generatedHeaderLines = 20
+
+// Matches the standard "Code generated ... DO NOT EDIT." header and the common @generated marker
+var generatedHeaderPattern = regexp.MustCompile(`^\s*(//|#|--|/?\*)\s*(Code generated .* DO NOT EDIT\.?|@generated\b)`)
+
+var ResolveGeneratedFileDefinition = ToolDefinition{
+	Name:        "resolve_generated_file",
+	Description: "Resolve a conflicted generated file (one with a 'Code generated ... DO NOT EDIT.' or '@generated' header) without merging it by hand. Runs the file's configured regeneration command if there is one, otherwise takes one side wholesale and stages it, clearing all conflict markers. see_git_status lists the conflicted files that look generated.",
+	InputSchema: ResolveGeneratedFileInputSchema,
+	Function:    ResolveGeneratedFile,
+}
+
+type ResolveGeneratedFileInput struct {
+	Path     string `json:"path" jsonschema_description:"The path to the conflicted generated file"`
+	TakeSide string `json:"take_side,omitempty" jsonschema_description:"Side to take wholesale when no regeneration command is configured: 'ours' or 'theirs'"`
+}
+
+var ResolveGeneratedFileInputSchema = GenerateSchema[ResolveGeneratedFileInput]()
+
+func ResolveGeneratedFile(ctx context.Context, input json.RawMessage) (string, error) {
+	var params ResolveGeneratedFileInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if err := ValidateFileExists(params.Path); err != nil {
+		return "", err
+	}
+	if err := checkProtected(params.Path); err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(params.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	if !IsGeneratedContent(string(content)) {
+		return "", fmt.Errorf("%s does not have a generated-code header; resolve its conflicts normally", params.Path)
+	}
+
+	// Prefer regenerating, which reflects both sides' inputs
+	if command, _ := findRegenerator(params.Path); command != "" {
+		result, err := regenerateFile(ctx, params.Path)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Treated %s as a generated file.\n%s", params.Path, result), nil
+	}
+
+	if params.TakeSide != "ours" && params.TakeSide != "theirs" {
+		return "", fmt.Errorf("no regeneration command is configured for %s; set take_side to 'ours' or 'theirs'", params.Path)
+	}
+
+	snapshotFile(params.Path)
+	if _, err := ExecuteGitCommand(ctx, "checkout", "--"+params.TakeSide, "--", params.Path); err != nil {
+		return "", fmt.Errorf("failed to take %s side of %s: %w", params.TakeSide, params.Path, err)
+	}
+
+	hasConflicts, err := HasMergeConflicts(params.Path)
+	if err != nil {
+		return "", err
+	}
+	if hasConflicts {
+		return fmt.Sprintf("Treated %s as a generated file and took the %s side, but it still contains conflict markers.",
+			params.Path, params.TakeSide), nil
+	}
+
+	// Stage the file like accept_version, so git no longer reports it as unmerged
+	if _, err := ExecuteGitCommand(ctx, "add", "--", params.Path); err != nil {
+		return "", fmt.Errorf("failed to stage %s: %w", params.Path, err)
+	}
+
+	return fmt.Sprintf("Treated %s as a generated file, took the %s side wholesale, and staged it; it no longer contains conflict markers.",
+		params.Path, params.TakeSide) + recordEdit(params.Path), nil
+}
+
+// IsGeneratedContent reports whether file content starts with a generated-code header
+func IsGeneratedContent(content string) bool {
+	lines := strings.SplitN(content, "\n", generatedHeaderLines+1)
+	for i, line := range lines {
+		if i == generatedHeaderLines {
+			break
+		}
+		if generatedHeaderPattern.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListGeneratedConflicts returns the unmerged files that look generated
+func ListGeneratedConflicts(ctx context.Context) ([]string, error) {
+	files, err := ListUnmergedFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var generated []string
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		if IsGeneratedContent(string(content)) {
+			generated = append(generated, file)
+		}
+	}
+	return generated, nil
+}
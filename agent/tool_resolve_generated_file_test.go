@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestResolveGeneratedFileStagesTakenSide(t *testing.T) {
+	initTestRepo(t)
+	const header = "// Code generated by protoc-gen-go. DO NOT EDIT.\n"
+	mergeWithConflicts(t,
+		map[string]string{"api.pb.go": header + "var x = 1\n"},
+		map[string]string{"api.pb.go": header + "var x = 2\n"},
+		map[string]string{"api.pb.go": header + "var x = 3\n"},
+	)
+
+	input, _ := json.Marshal(ResolveGeneratedFileInput{Path: "api.pb.go", TakeSide: "theirs"})
+	if _, err := ResolveGeneratedFile(context.Background(), input); err != nil {
+		t.Fatalf("ResolveGeneratedFile() error = %v", err)
+	}
+	if got, want := readTempFile(t, "api.pb.go"), header+"var x = 3\n"; got != want {
+		t.Errorf("api.pb.go = %q, want %q", got, want)
+	}
+
+	unmerged, err := ListUnmergedFiles(context.Background())
+	if err != nil {
+		t.Fatalf("ListUnmergedFiles() error = %v", err)
+	}
+	if len(unmerged) != 0 {
+		t.Errorf("unmerged files = %v, want api.pb.go staged", unmerged)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var ResolveIdenticalChunksDefinition = ToolDefinition{
+	Name:        "resolve_identical_chunks",
+	Description: "Find conflict chunks whose two sides are byte-identical and resolve them by keeping the shared content. Checks a single file, or every unmerged file if no path is given, listing any it had to skip. Run this first, before resolving any chunks by hand.",
+	InputSchema: ResolveIdenticalChunksInputSchema,
+	Function:    ResolveIdenticalChunks,
+}
+
+type ResolveIdenticalChunksInput struct {
+	Path string `json:"path,omitempty" jsonschema_description:"Optional path to a single file to check. Defaults to all unmerged files."`
+}
+
+var ResolveIdenticalChunksInputSchema = GenerateSchema[ResolveIdenticalChunksInput]()
+
+func ResolveIdenticalChunks(ctx context.Context, input json.RawMessage) (string, error) {
+	var params ResolveIdenticalChunksInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	paths := []string{params.Path}
+	if params.Path == "" {
+		unmerged, err := ListUnmergedFiles(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to list unmerged files: %w", err)
+		}
+		paths = unmerged
+	}
+
+	var result strings.Builder
+	var skipped []string
+	total := 0
+	for _, path := range paths {
+		// Files that cannot be resolved are reported instead of failing the whole batch
+		if params.Path == "" && checkProtected(path) != nil {
+			skipped = append(skipped, fmt.Sprintf("%s (protected by the repository's GitSynth config)", path))
+			continue
+		}
+		resolved, err := resolveIdenticalChunksInFile(path)
+		if err != nil {
+			if params.Path != "" {
+				return "", fmt.Errorf("failed to resolve identical chunks in %s: %w", path, err)
+			}
+			skipped = append(skipped, fmt.Sprintf("%s (%v)", path, err))
+			continue
+		}
+		if resolved > 0 {
+			result.WriteString(fmt.Sprintf("%s: resolved %d identical chunks\n", path, resolved))
+			total += resolved
+		}
+	}
+
+	result.WriteString(skippedFilesSection(skipped))
+
+	if total == 0 {
+		return result.String() + "No conflict chunks with identical sides found", nil
+	}
+	return fmt.Sprintf("%s\nResolved %d identical chunks in total. Remaining chunk IDs have been renumbered.", result.String(), total), nil
+}
+
+// resolveIdenticalChunksInFile replaces every chunk whose sides match with the shared content
+func resolveIdenticalChunksInFile(path string) (int, error) {
+	if err := ValidateFileExists(path); err != nil {
+		return 0, err
+	}
+	if err := checkProtected(path); err != nil {
+		return 0, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	chunks, err := FindConflictChunks(string(content))
+	if err != nil {
+		return 0, err
+	}
+
+	// Go from the last chunk up so earlier chunk IDs stay valid
+	resolved := 0
+	for i := len(chunks) - 1; i >= 0; i-- {
+		if chunks[i].BaseCode != chunks[i].IncomingCode {
+			continue
+		}
+		if err := ReplaceConflictChunk(path, chunks[i].ID, chunks[i].BaseCode); err != nil {
+			return resolved, err
+		}
+		resolved++
+	}
+
+	return resolved, nil
+}
+
+// skippedFilesSection lists the files a batch resolution skipped and why, or is empty if none were
+func skippedFilesSection(skipped []string) string {
+	if len(skipped) == 0 {
+		return ""
+	}
+	var section strings.Builder
+	section.WriteString("Skipped files:\n")
+	for _, entry := range skipped {
+		section.WriteString(fmt.Sprintf("  %s\n", entry))
+	}
+	return section.String()
+}
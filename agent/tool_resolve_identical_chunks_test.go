@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestResolveIdenticalChunksInFile(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantResolved int
+		wantContent  string
+	}{
+		{
+			name:         "identical sides",
+			content:      "a\n<<<<<<< HEAD\nsame\n=======\nsame\n>>>>>>> b\nc\n",
+			wantResolved: 1,
+			wantContent:  "a\nsame\nc\n",
+		},
+		{
+			name:         "empty ours is not identical to theirs",
+			content:      "a\n<<<<<<< HEAD\n=======\ntheirs1\n>>>>>>> b",
+			wantResolved: 0,
+			wantContent:  "a\n<<<<<<< HEAD\n=======\ntheirs1\n>>>>>>> b",
+		},
+		{
+			name:         "empty ours does not leak into the next chunk",
+			content:      "<<<<<<< HEAD\n=======\nx\n>>>>>>> b\n<<<<<<< HEAD\ny\n=======\nx\ny\n>>>>>>> b\n",
+			wantResolved: 0,
+			wantContent:  "<<<<<<< HEAD\n=======\nx\n>>>>>>> b\n<<<<<<< HEAD\ny\n=======\nx\ny\n>>>>>>> b\n",
+		},
+		{
+			name:         "only the identical chunk is resolved",
+			content:      "<<<<<<< HEAD\n1\n=======\n2\n>>>>>>> b\n<<<<<<< HEAD\n3\n=======\n3\n>>>>>>> b\n",
+			wantResolved: 1,
+			wantContent:  "<<<<<<< HEAD\n1\n=======\n2\n>>>>>>> b\n3\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTempFile(t, "file.txt", tt.content)
+			resolved, err := resolveIdenticalChunksInFile(path)
+			if err != nil {
+				t.Fatalf("resolveIdenticalChunksInFile() error = %v", err)
+			}
+			if resolved != tt.wantResolved {
+				t.Errorf("resolved %d chunks, want %d", resolved, tt.wantResolved)
+			}
+			if got := readTempFile(t, path); got != tt.wantContent {
+				t.Errorf("content = %q, want %q", got, tt.wantContent)
+			}
+		})
+	}
+}
+
+func TestResolveIdenticalChunksSkipsProtectedFiles(t *testing.T) {
+	initTestRepo(t)
+	mergeWithConflicts(t,
+		map[string]string{"protected.txt": "base\n", "open.txt": "base\n"},
+		map[string]string{"protected.txt": "ours\n", "open.txt": "ours\n"},
+		map[string]string{"protected.txt": "theirs\n", "open.txt": "theirs\n"},
+	)
+	// Give both files a chunk with identical sides
+	const identical = "<<<<<<< HEAD\nsame\n=======\nsame\n>>>>>>> feature\n"
+	writeTree(t, map[string]string{"protected.txt": identical, "open.txt": identical})
+
+	protectedPatterns = []string{"protected.txt"}
+	t.Cleanup(func() { protectedPatterns = nil })
+
+	result, err := ResolveIdenticalChunks(context.Background(), json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("ResolveIdenticalChunks() error = %v", err)
+	}
+	if !strings.Contains(result, "Skipped files:\n  protected.txt (protected") || !strings.Contains(result, "open.txt: resolved 1") {
+		t.Errorf("ResolveIdenticalChunks() = %q, want protected.txt skipped and open.txt resolved", result)
+	}
+	if got := readTempFile(t, "protected.txt"); got != identical {
+		t.Errorf("protected.txt = %q, want it untouched", got)
+	}
+	if got := readTempFile(t, "open.txt"); got != "same\n" {
+		t.Errorf("open.txt = %q, want the shared content", got)
+	}
+
+	input, _ := json.Marshal(ResolveIdenticalChunksInput{Path: "protected.txt"})
+	if _, err := ResolveIdenticalChunks(context.Background(), input); err == nil {
+		t.Error("ResolveIdenticalChunks(protected.txt) succeeded, want an error")
+	}
+	if got := readTempFile(t, "protected.txt"); got != identical {
+		t.Errorf("protected.txt = %q, want it untouched", got)
+	}
+}
+
+func TestResolveIdenticalChunksSkipsUnreadableFiles(t *testing.T) {
+	initTestRepo(t)
+	mergeWithConflicts(t,
+		map[string]string{"broken.txt": "base\n", "deleted.txt": "base\n", "open.txt": "base\n"},
+		map[string]string{"broken.txt": "ours\n", "deleted.txt": "ours\n", "open.txt": "ours\n"},
+		map[string]string{"broken.txt": "theirs\n", "deleted.txt": "theirs\n", "open.txt": "theirs\n"},
+	)
+	const identical = "<<<<<<< HEAD\nsame\n=======\nsame\n>>>>>>> feature\n"
+	writeTree(t, map[string]string{"broken.txt": "<<<<<<< HEAD\nunclosed\n", "open.txt": identical})
+	if err := os.Remove("deleted.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ResolveIdenticalChunks(context.Background(), json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("ResolveIdenticalChunks() error = %v", err)
+	}
+	for _, want := range []string{"open.txt: resolved 1", "Skipped files:", "  broken.txt (unclosed conflict marker", "  deleted.txt (file deleted.txt does not exist)"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("ResolveIdenticalChunks() = %q, want it to contain %q", result, want)
+		}
+	}
+
+	input, _ := json.Marshal(ResolveIdenticalChunksInput{Path: "broken.txt"})
+	if _, err := ResolveIdenticalChunks(context.Background(), input); err == nil {
+		t.Error("ResolveIdenticalChunks(broken.txt) succeeded, want an error")
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Line-oriented files whose conflicts are best resolved by keeping the lines of both sides
+var unionFriendlyFiles = map[string]bool{
+	".gitignore":       true,
+	".dockerignore":    true,
+	"CODEOWNERS":       true,
+	"requirements.txt": true,
+}
+
+var ResolveUnionFilesDefinition = ToolDefinition{
+	Name:        "resolve_union_files",
+	Description: "Auto-resolve conflicts in line-oriented files where both sides' lines should be kept (.gitignore, .dockerignore, CODEOWNERS, requirements.txt). Each chunk is replaced by the unique non-empty lines of both sides, ours first, skipping lines already elsewhere in the file. Checks a single file, or every unmerged file if no path is given, listing any it had to skip.",
+	InputSchema: ResolveUnionFilesInputSchema,
+	Function:    ResolveUnionFiles,
+}
+
+type ResolveUnionFilesInput struct {
+	Path string `json:"path,omitempty" jsonschema_description:"Optional path to a single file to resolve. Defaults to all unmerged files."`
+}
+
+var ResolveUnionFilesInputSchema = GenerateSchema[ResolveUnionFilesInput]()
+
+func ResolveUnionFiles(ctx context.Context, input json.RawMessage) (string, error) {
+	var params ResolveUnionFilesInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	paths := []string{params.Path}
+	if params.Path == "" {
+		unmerged, err := ListUnmergedFiles(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to list unmerged files: %w", err)
+		}
+		paths = unmerged
+	} else if !IsUnionFriendly(params.Path) {
+		return "", fmt.Errorf("%s is not a line-union file; resolve its conflicts normally", params.Path)
+	}
+
+	var result strings.Builder
+	var skipped []string
+	total := 0
+	for _, path := range paths {
+		if !IsUnionFriendly(path) {
+			continue
+		}
+		// Files that cannot be resolved are reported instead of failing the whole batch
+		if params.Path == "" && checkProtected(path) != nil {
+			skipped = append(skipped, fmt.Sprintf("%s (protected by the repository's GitSynth config)", path))
+			continue
+		}
+		resolved, err := resolveUnionChunksInFile(path)
+		if err != nil {
+			if params.Path != "" {
+				return "", fmt.Errorf("failed to union chunks in %s: %w", path, err)
+			}
+			skipped = append(skipped, fmt.Sprintf("%s (%v)", path, err))
+			continue
+		}
+		if resolved > 0 {
+			result.WriteString(fmt.Sprintf("%s: resolved %d chunks as a union of both sides\n", path, resolved))
+			total += resolved
+		}
+	}
+
+	result.WriteString(skippedFilesSection(skipped))
+
+	if total == 0 {
+		return result.String() + "No conflicts found in line-union files", nil
+	}
+	return fmt.Sprintf("%s\nResolved %d chunks in total. Review the results: a union keeps both versions of a changed line.", result.String(), total), nil
+}
+
+// IsUnionFriendly reports whether a file's conflicts can be resolved as a union of lines
+func IsUnionFriendly(path string) bool {
+	return unionFriendlyFiles[filepath.Base(path)]
+}
+
+// resolveUnionChunksInFile replaces every chunk with the union of its sides' lines
+func resolveUnionChunksInFile(path string) (int, error) {
+	if err := ValidateFileExists(path); err != nil {
+		return 0, err
+	}
+	if err := checkProtected(path); err != nil {
+		return 0, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	chunks, err := FindConflictChunks(string(content))
+	if err != nil {
+		return 0, err
+	}
+
+	// Lines outside every chunk are already in the file and should not be repeated
+	lines := strings.Split(string(content), "\n")
+	existing := make(map[string]bool)
+	next := 0
+	for _, chunk := range chunks {
+		for _, line := range lines[next : chunk.StartLine-1] {
+			existing[strings.TrimSpace(line)] = true
+		}
+		next = chunk.EndLine
+	}
+	for _, line := range lines[next:] {
+		existing[strings.TrimSpace(line)] = true
+	}
+
+	// Go from the last chunk up so earlier chunk IDs stay valid
+	for i := len(chunks) - 1; i >= 0; i-- {
+		union := UnionLines(existing, chunks[i].BaseCode, chunks[i].IncomingCode)
+		if err := ReplaceConflictChunk(path, chunks[i].ID, union); err != nil {
+			return len(chunks) - 1 - i, err
+		}
+	}
+
+	return len(chunks), nil
+}
+
+// UnionLines returns the unique non-empty lines of the given sides in order, skipping any in exclude
+func UnionLines(exclude map[string]bool, sides ...string) string {
+	seen := make(map[string]bool)
+	var union []string
+	for _, side := range sides {
+		for _, line := range strings.Split(side, "\n") {
+			key := strings.TrimSpace(line)
+			if key == "" || seen[key] || exclude[key] {
+				continue
+			}
+			seen[key] = true
+			union = append(union, line)
+		}
+	}
+	return strings.Join(union, "\n")
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestResolveUnionFilesGitignore(t *testing.T) {
+	initTestRepo(t)
+	mergeWithConflicts(t,
+		map[string]string{".gitignore": "node_modules/\n*.log\n", "main.go": "package main\n"},
+		map[string]string{".gitignore": "node_modules/\n*.log\ndist/\n.env\n\ncoverage/\n", "main.go": "package main // ours\n"},
+		map[string]string{".gitignore": "node_modules/\n*.log\n.env\nbuild/\n*.log\n", "main.go": "package main // theirs\n"},
+	)
+
+	result, err := ResolveUnionFiles(context.Background(), json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("ResolveUnionFiles() error = %v", err)
+	}
+	if !strings.Contains(result, ".gitignore: resolved 1 chunks") {
+		t.Errorf("ResolveUnionFiles() = %q, want it to report the .gitignore resolution", result)
+	}
+	if strings.Contains(result, "main.go") {
+		t.Errorf("ResolveUnionFiles() = %q, want main.go left alone", result)
+	}
+
+	want := "node_modules/\n*.log\ndist/\n.env\ncoverage/\nbuild/\n"
+	if got := readTempFile(t, ".gitignore"); got != want {
+		t.Errorf(".gitignore = %q, want %q", got, want)
+	}
+	if got := readTempFile(t, "main.go"); !strings.Contains(got, "<<<<<<<") {
+		t.Errorf("main.go = %q, want its conflict untouched", got)
+	}
+}
+
+func TestResolveUnionFilesSkipsUnparsableFiles(t *testing.T) {
+	initTestRepo(t)
+	mergeWithConflicts(t,
+		map[string]string{".gitignore": "*.log\n", "sub/.gitignore": "*.tmp\n"},
+		map[string]string{".gitignore": "*.log\ndist/\n", "sub/.gitignore": "*.tmp\nout/\n"},
+		map[string]string{".gitignore": "*.log\nbuild/\n", "sub/.gitignore": "*.tmp\ncache/\n"},
+	)
+	writeTree(t, map[string]string{"sub/.gitignore": "*.tmp\n<<<<<<< HEAD\nout/\n"})
+
+	result, err := ResolveUnionFiles(context.Background(), json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("ResolveUnionFiles() error = %v", err)
+	}
+	if !strings.Contains(result, ".gitignore: resolved 1 chunks") || !strings.Contains(result, "Skipped files:\n  sub/.gitignore (unclosed conflict marker") {
+		t.Errorf("ResolveUnionFiles() = %q, want .gitignore resolved and sub/.gitignore skipped", result)
+	}
+
+	input, _ := json.Marshal(ResolveUnionFilesInput{Path: "sub/.gitignore"})
+	if _, err := ResolveUnionFiles(context.Background(), input); err == nil {
+		t.Error("ResolveUnionFiles(sub/.gitignore) succeeded, want an error")
+	}
+}
+
+func TestResolveUnionFilesRejectsOtherFiles(t *testing.T) {
+	input, _ := json.Marshal(ResolveUnionFilesInput{Path: "main.go"})
+	if _, err := ResolveUnionFiles(context.Background(), input); err == nil {
+		t.Error("ResolveUnionFiles(main.go) succeeded, want an error for a file that is not line-union friendly")
+	}
+}
+
+func TestUnionLines(t *testing.T) {
+	got := UnionLines(map[string]bool{"a": true}, "a\nb\n\nc", "c\nd\nb")
+	if want := "b\nc\nd"; got != want {
+		t.Errorf("UnionLines() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+var RestoreAllDefinition = ToolDefinition{
+	Name:        "restore_all",
+	Description: "Revert every change made this session by restoring the working tree from the backup taken before the first modification, and deleting files created since. Files whose conflicts were staged since are marked unmerged again. Only available when GitSynth runs with -backup-dir. Does not undo commits made with git_save_changes or unstage files created since the backup.",
+	InputSchema: RestoreAllInputSchema,
+	Function:    RestoreAll,
+}
+
+type RestoreAllInput struct {
+	// No parameters needed for this tool
+}
+
+var RestoreAllInputSchema = GenerateSchema[RestoreAllInput]()
+
+func RestoreAll(ctx context.Context, input json.RawMessage) (string, error) {
+	restored, removed, reconflicted, err := restoreBackup(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	result := fmt.Sprintf("Restored %d file(s) from the backup in %s", restored, backupDir)
+	if len(removed) > 0 {
+		result += fmt.Sprintf("\n\nRemoved %d file(s) created since the backup:\n  %s", len(removed), strings.Join(removed, "\n  "))
+	}
+	if len(reconflicted) > 0 {
+		result += fmt.Sprintf("\n\nMarked %d file(s) staged since the backup as unmerged again:\n  %s", len(reconflicted), strings.Join(reconflicted, "\n  "))
+	}
+	return result, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var RestoreFileDefinition = ToolDefinition{
+	Name:        "restore_file",
+	Description: "Revert a file to its contents before it was first modified since the last git_save_changes, undoing every edit to it in between. Use this when a resolution has gone wrong and it is easier to start the file over. Conflict markers that were present before the edits come back.",
+	InputSchema: RestoreFileInputSchema,
+	Function:    RestoreFile,
+}
+
+type RestoreFileInput struct {
+	Path string `json:"path" jsonschema_description:"The path of the file to restore"`
+}
+
+var RestoreFileInputSchema = GenerateSchema[RestoreFileInput]()
+
+func RestoreFile(ctx context.Context, input json.RawMessage) (string, error) {
+	params := RestoreFileInput{}
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+	if params.Path == "" {
+		return "", fmt.Errorf("path cannot be empty")
+	}
+
+	if err := restoreSnapshot(params.Path); err != nil {
+		if files := snapshottedFiles(); len(files) > 0 {
+			return "", fmt.Errorf("%w. Files that can be restored:\n  %s", err, strings.Join(files, "\n  "))
+		}
+		return "", err
+	}
+
+	if _, err := os.Stat(params.Path); os.IsNotExist(err) {
+		return fmt.Sprintf("Removed %s, which did not exist before it was modified", params.Path), nil
+	}
+
+	resetConflictRegions(params.Path)
+	chunks := 0
+	if content, err := os.ReadFile(params.Path); err == nil {
+		if found, err := FindConflictChunks(string(content)); err == nil {
+			chunks = len(found)
+		}
+	}
+	return fmt.Sprintf("Restored %s to its contents before it was modified. It has %d conflict chunk(s); view it again before editing.", params.Path, chunks), nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Maximum number of diff lines review_file returns before truncating
+const maxReviewDiffLines = 400
+
+var ReviewFileDefinition = ToolDefinition{
+	Name:        "review_file",
+	Description: "Show a unified diff between a file's committed state before the merge and its current resolved contents, i.e. the net effect of the resolution. Compares against HEAD by default, or against the merge base of HEAD and MERGE_HEAD. Very large diffs are truncated.",
+	InputSchema: ReviewFileInputSchema,
+	Function:    ReviewFile,
+}
+
+type ReviewFileInput struct {
+	Path         string `json:"path" jsonschema_description:"The path to the resolved file to review"`
+	UseMergeBase bool   `json:"use_merge_base,omitempty" jsonschema_description:"If true, compare against the merge base of HEAD and MERGE_HEAD instead of HEAD"`
+}
+
+var ReviewFileInputSchema = GenerateSchema[ReviewFileInput]()
+
+func ReviewFile(ctx context.Context, input json.RawMessage) (string, error) {
+	var params ReviewFileInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if err := ValidateFileExists(params.Path); err != nil {
+		return "", err
+	}
+
+	base, baseLabel := "HEAD", "HEAD"
+	if params.UseMergeBase {
+		if !IsMergeInProgress(ctx) {
+			return "", fmt.Errorf("no merge is in progress (MERGE_HEAD does not exist), so there is no merge base")
+		}
+		mergeBase, err := ExecuteGitCommand(ctx, "merge-base", "HEAD", "MERGE_HEAD")
+		if err != nil {
+			return "", fmt.Errorf("failed to find merge base: %w", err)
+		}
+		base, baseLabel = mergeBase, fmt.Sprintf("the merge base (%.7s)", mergeBase)
+	}
+
+	// With one revision, git diff compares that revision to the working tree
+	diff, err := ExecuteGitCommand(ctx, "diff", base, "--", params.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to diff file: %w", err)
+	}
+
+	if diff == "" {
+		return fmt.Sprintf("%s is unchanged relative to %s", params.Path, baseLabel), nil
+	}
+
+	lines := strings.Split(diff, "\n")
+	truncated := ""
+	if len(lines) > maxReviewDiffLines {
+		truncated = fmt.Sprintf("\n\n[Diff truncated: showing %d of %d lines. Use view_file or review_resolution to inspect the rest.]",
+			maxReviewDiffLines, len(lines))
+		lines = lines[:maxReviewDiffLines]
+	}
+
+	return fmt.Sprintf("Changes to %s relative to %s (-) after resolution (+):\n\n%s%s",
+		params.Path, baseLabel, strings.Join(lines, "\n"), truncated), nil
+}
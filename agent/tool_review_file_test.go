@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestReviewFile(t *testing.T) {
+	initTestRepo(t)
+	mergeWithConflicts(t,
+		map[string]string{"f.txt": "header\nbase value\n"},
+		map[string]string{"f.txt": "header\nours value\n"},
+		map[string]string{"f.txt": "header\ntheirs value\n"},
+	)
+	writeTree(t, map[string]string{"f.txt": "header\nresolved value\n"})
+
+	tests := []struct {
+		name  string
+		input ReviewFileInput
+		want  []string
+	}{
+		{"against HEAD", ReviewFileInput{Path: "f.txt"}, []string{"relative to HEAD", "-ours value", "+resolved value"}},
+		{"against the merge base", ReviewFileInput{Path: "f.txt", UseMergeBase: true}, []string{"relative to the merge base", "-base value", "+resolved value"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, _ := json.Marshal(tt.input)
+			got, err := ReviewFile(context.Background(), input)
+			if err != nil {
+				t.Fatalf("ReviewFile() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("ReviewFile() = %q, want it to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReviewFileTruncatesLongDiffs(t *testing.T) {
+	initTestRepo(t)
+	commitFiles(t, "base", map[string]string{"big.txt": strings.Repeat("old\n", maxReviewDiffLines)})
+	writeTree(t, map[string]string{"big.txt": strings.Repeat("new\n", maxReviewDiffLines)})
+
+	input, _ := json.Marshal(ReviewFileInput{Path: "big.txt"})
+	got, err := ReviewFile(context.Background(), input)
+	if err != nil {
+		t.Fatalf("ReviewFile() error = %v", err)
+	}
+	if !strings.Contains(got, "[Diff truncated: showing 400 of") {
+		t.Errorf("ReviewFile() = %.200q..., want the truncation notice", got)
+	}
+	if count := strings.Count(got, "\n+new"); count >= maxReviewDiffLines {
+		t.Errorf("ReviewFile() shows %d added lines, want the diff cut short", count)
+	}
+}
+
+func TestReviewFileMergeBaseWithoutMerge(t *testing.T) {
+	initTestRepo(t)
+	commitFiles(t, "base", map[string]string{"f.txt": "base\n"})
+
+	input, _ := json.Marshal(ReviewFileInput{Path: "f.txt", UseMergeBase: true})
+	if _, err := ReviewFile(context.Background(), input); err == nil {
+		t.Error("ReviewFile(use_merge_base) succeeded without a merge, want an error")
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var ReviewResolutionDefinition = ToolDefinition{
+	Name:        "review_resolution",
+	Description: "Review a resolved file against both sides of the merge. Annotates each line with where it came from: ours (index stage 2), theirs (index stage 3), both, or new (written during resolution). Optionally limit the output to a line range.",
+	InputSchema: ReviewResolutionInputSchema,
+	Function:    ReviewResolution,
+}
+
+type ReviewResolutionInput struct {
+	Path      string `json:"path" jsonschema_description:"The path to the resolved file to review"`
+	StartLine int    `json:"start_line,omitempty" jsonschema_description:"Optional first line to show (1-indexed). Defaults to the start of the file."`
+	EndLine   int    `json:"end_line,omitempty" jsonschema_description:"Optional last line to show (inclusive, 1-indexed). Defaults to the end of the file."`
+}
+
+var ReviewResolutionInputSchema = GenerateSchema[ReviewResolutionInput]()
+
+// Provenance labels for resolved lines
+const (
+	provenanceBoth   = "both"
+	provenanceOurs   = "ours"
+	provenanceTheirs = "theirs"
+	provenanceNew    = "new"
+)
+
+func ReviewResolution(ctx context.Context, input json.RawMessage) (string, error) {
+	var params ReviewResolutionInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if err := ValidateFileExists(params.Path); err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(params.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// A missing side (e.g. the file was added on one branch) simply contributes no lines
+	ours, oursErr := GetFileVersionAtStage(ctx, params.Path, 2)
+	theirs, theirsErr := GetFileVersionAtStage(ctx, params.Path, 3)
+	if oursErr != nil && theirsErr != nil {
+		return "", fmt.Errorf("no conflict stages found for %s, is the file part of an in-progress merge?", params.Path)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	startLine, endLine := params.StartLine, params.EndLine
+	if startLine < 1 {
+		startLine = 1
+	}
+	if endLine < 1 || endLine > len(lines) {
+		endLine = len(lines)
+	}
+	if startLine > endLine {
+		return "", fmt.Errorf("start_line %d is beyond end_line %d", startLine, endLine)
+	}
+
+	labels := AttributeLines(lines, splitSide(ours, oursErr), splitSide(theirs, theirsErr))
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("File: %s (lines %d-%d)\n\n", params.Path, startLine, endLine))
+
+	counts := make(map[string]int)
+	width := len(fmt.Sprintf("%d", endLine))
+	for i := startLine - 1; i < endLine; i++ {
+		counts[labels[i]]++
+		result.WriteString(fmt.Sprintf("%*d [%-6s] | %s\n", width, i+1, labels[i], lines[i]))
+	}
+
+	result.WriteString(fmt.Sprintf("\nSummary: %d both, %d ours, %d theirs, %d new\n",
+		counts[provenanceBoth], counts[provenanceOurs], counts[provenanceTheirs], counts[provenanceNew]))
+
+	return result.String(), nil
+}
+
+// AttributeLines labels each resolved line by which side(s) of the merge contain it
+func AttributeLines(resolved, ours, theirs []string) []string {
+	inOurs := lineSet(ours)
+	inTheirs := lineSet(theirs)
+
+	labels := make([]string, len(resolved))
+	for i, line := range resolved {
+		switch {
+		case inOurs[line] && inTheirs[line]:
+			labels[i] = provenanceBoth
+		case inOurs[line]:
+			labels[i] = provenanceOurs
+		case inTheirs[line]:
+			labels[i] = provenanceTheirs
+		default:
+			labels[i] = provenanceNew
+		}
+	}
+	return labels
+}
+
+// splitSide splits the content of one side into lines, treating a missing side as empty
+func splitSide(content string, err error) []string {
+	if err != nil {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
+
+// lineSet builds a lookup of the lines in a file
+func lineSet(lines []string) map[string]bool {
+	set := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		set[line] = true
+	}
+	return set
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestReviewResolutionAttributesLines(t *testing.T) {
+	initTestRepo(t)
+	mergeWithConflicts(t,
+		map[string]string{"app.conf": "header\nvalue = 1\n"},
+		map[string]string{"app.conf": "header\nvalue = 2\n"},
+		map[string]string{"app.conf": "header\nvalue = 3\n"},
+	)
+	writeTree(t, map[string]string{"app.conf": "header\nvalue = 2\nvalue = 3\n# resolved by hand\n"})
+
+	input, _ := json.Marshal(ReviewResolutionInput{Path: "app.conf", EndLine: 4})
+	got, err := ReviewResolution(context.Background(), input)
+	if err != nil {
+		t.Fatalf("ReviewResolution() error = %v", err)
+	}
+	for _, want := range []string{
+		"1 [both  ] | header",
+		"2 [ours  ] | value = 2",
+		"3 [theirs] | value = 3",
+		"4 [new   ] | # resolved by hand",
+		"Summary: 1 both, 1 ours, 1 theirs, 1 new",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ReviewResolution() = %q, want it to contain %q", got, want)
+		}
+	}
+}
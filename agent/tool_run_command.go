@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Command prefixes the agent may run with run_command, in addition to the test command
+var allowedCommands []string
+
+// Characters that could chain or redirect a command past its allowlisted prefix
+const shellMetacharacters = ";&|`$<>()\n\\"
+
+// Maximum characters of command output returned to the agent; the end is kept, since that is where errors usually are
+const maxCommandOutput = 10000
+
+var RunCommandDefinition = ToolDefinition{
+	Name:        "run_command",
+	Description: "Run a build or test command in the repository root and return its exit code and output, to check that the resolution compiles and passes before saving. Only the configured test command and commands allowed by the 'allowed_commands' config can run. Omit the command to run the test command.",
+	InputSchema: RunCommandInputSchema,
+	Function:    RunCommand,
+}
+
+type RunCommandInput struct {
+	Command string `json:"command,omitempty" jsonschema_description:"Optional command to run such as 'go build ./...'. Must be the test command or start with an allowed command. Defaults to the test command."`
+}
+
+var RunCommandInputSchema = GenerateSchema[RunCommandInput]()
+
+func RunCommand(ctx context.Context, input json.RawMessage) (string, error) {
+	var params RunCommandInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	command := strings.TrimSpace(params.Command)
+	if command == "" {
+		command = testCommand
+	}
+	if command == "" {
+		return "", fmt.Errorf("no command given and no test command is configured; set test_command in .gitsynth.yml")
+	}
+	if err := checkCommandAllowed(command); err != nil {
+		return "", err
+	}
+
+	root, err := ExecuteGitCommand(ctx, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("failed to find the repository root: %w", err)
+	}
+
+	cmd := toolCommand(ctx, "sh", "-c", command)
+	cmd.Dir = root
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	exitCode := 0
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return "", fmt.Errorf("failed to run %q: %w", command, err)
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	text := output.String()
+	if len(text) > maxCommandOutput {
+		text = fmt.Sprintf("... (%d earlier characters omitted)\n%s", len(text)-maxCommandOutput, text[len(text)-maxCommandOutput:])
+	}
+	if strings.TrimSpace(text) == "" {
+		text = "(no output)"
+	}
+	return fmt.Sprintf("$ %s\nExit code: %d\n\n%s", command, exitCode, text), nil
+}
+
+// checkCommandAllowed returns an error unless the command is the configured test command or
+// starts with an allowed command. Everything is denied when nothing is configured.
+func checkCommandAllowed(command string) error {
+	if command == testCommand {
+		return nil
+	}
+	if strings.ContainsAny(command, shellMetacharacters) {
+		return fmt.Errorf("refusing to run %q: shell operators are not allowed, run one command at a time", command)
+	}
+	for _, allowed := range allowedCommands {
+		allowed = strings.TrimSpace(allowed)
+		if allowed != "" && (command == allowed || strings.HasPrefix(command, allowed+" ")) {
+			return nil
+		}
+	}
+	if len(allowedCommands) == 0 {
+		return fmt.Errorf("refusing to run %q: no commands are allowed; add them to allowed_commands in .gitsynth.yml", command)
+	}
+	return fmt.Errorf("refusing to run %q: allowed commands are %s", command, strings.Join(allowedCommands, ", "))
+}
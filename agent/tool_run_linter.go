@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Mapping of file name glob patterns to the linter command run on matching files
+var linters = map[string]string{}
+
+// Matches the "path:line:" prefix most linters use for findings
+var lintFindingPattern = regexp.MustCompile(`^(.+?):(\d+)(?::\d+)?:`)
+
+// Matches the new-file line range of a unified diff hunk header
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+var RunLinterDefinition = ToolDefinition{
+	Name:        "run_linter",
+	Description: "Run the configured linters (from the 'linters' section of the GitSynth config) on files changed relative to HEAD, and report only findings on lines the resolution changed, so pre-existing issues are left out. Does nothing when no linter is configured.",
+	InputSchema: RunLinterInputSchema,
+	Function:    RunLinter,
+}
+
+type RunLinterInput struct {
+	Path string `json:"path,omitempty" jsonschema_description:"Optional single file to lint. Defaults to every file changed relative to HEAD."`
+}
+
+var RunLinterInputSchema = GenerateSchema[RunLinterInput]()
+
+func RunLinter(ctx context.Context, input json.RawMessage) (string, error) {
+	var params RunLinterInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if len(linters) == 0 {
+		return "No linter is configured, skipping. Add a 'linters' section to the GitSynth config to enable this.", nil
+	}
+
+	files := []string{params.Path}
+	if params.Path == "" {
+		output, err := ExecuteGitCommand(ctx, "diff", "--name-only", "HEAD")
+		if err != nil {
+			return "", fmt.Errorf("failed to list changed files: %w", err)
+		}
+		if output == "" {
+			return "No files have changed relative to HEAD", nil
+		}
+		files = strings.Split(output, "\n")
+	}
+
+	// Group files by the linter command that applies to them
+	byCommand := make(map[string][]string)
+	for _, file := range files {
+		if command, _ := matchPatternMap(linters, file); command != "" {
+			byCommand[command] = append(byCommand[command], file)
+		}
+	}
+	if len(byCommand) == 0 {
+		return "No configured linter applies to the changed files", nil
+	}
+
+	commands := make([]string, 0, len(byCommand))
+	for command := range byCommand {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+
+	var result strings.Builder
+	total := 0
+	for _, command := range commands {
+		touched := make(map[string]map[int]bool)
+		for _, file := range byCommand[command] {
+			lines, err := ChangedLines(ctx, file)
+			if err != nil {
+				return "", err
+			}
+			touched[filepath.Clean(file)] = lines
+		}
+
+		output := runLinterCommand(ctx, command, byCommand[command])
+		findings := FilterLintFindings(output, touched)
+		total += len(findings)
+		for _, finding := range findings {
+			result.WriteString(finding + "\n")
+		}
+	}
+
+	if total == 0 {
+		return fmt.Sprintf("Linted %d file(s): no findings on lines changed by the resolution", len(files)), nil
+	}
+	return fmt.Sprintf("Found %d lint finding(s) on lines changed by the resolution:\n\n%s", total, result.String()), nil
+}
+
+// runLinterCommand runs a linter command with the files appended as arguments and returns its
+// combined output. Linters exit non-zero when they report findings, so the exit status is ignored.
+func runLinterCommand(ctx context.Context, command string, files []string) string {
+	quoted := make([]string, len(files))
+	for i, file := range files {
+		quoted[i] = "'" + strings.ReplaceAll(file, "'", `'\''`) + "'"
+	}
+
+	cmd := toolCommand(ctx, "sh", "-c", command+" "+strings.Join(quoted, " "))
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	_ = cmd.Run()
+	return output.String()
+}
+
+// ChangedLines returns the line numbers of a file's working tree contents that differ from HEAD
+func ChangedLines(ctx context.Context, path string) (map[int]bool, error) {
+	diff, err := ExecuteGitCommand(ctx, "diff", "-U0", "HEAD", "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s: %w", path, err)
+	}
+
+	lines := make(map[int]bool)
+	for _, line := range strings.Split(diff, "\n") {
+		match := hunkHeaderPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		start, _ := strconv.Atoi(match[1])
+		count := 1
+		if match[2] != "" {
+			count, _ = strconv.Atoi(match[2])
+		}
+		for i := start; i < start+count; i++ {
+			lines[i] = true
+		}
+	}
+	return lines, nil
+}
+
+// FilterLintFindings keeps the "path:line:" lines of linter output that point at touched lines
+func FilterLintFindings(output string, touched map[string]map[int]bool) []string {
+	var findings []string
+	for _, line := range strings.Split(output, "\n") {
+		match := lintFindingPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		lineNum, _ := strconv.Atoi(match[2])
+		if touched[filepath.Clean(match[1])][lineNum] {
+			findings = append(findings, strings.TrimSpace(line))
+		}
+	}
+	return findings
+}
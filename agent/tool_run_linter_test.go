@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunLinterKeepsFindingsOnChangedLines(t *testing.T) {
+	initTestRepo(t)
+	commitFiles(t, "base", map[string]string{"notes.txt": "one\ntwo\nthree\nfour\nfive\n"})
+	writeTree(t, map[string]string{"notes.txt": "one\ntwo\nTHREE\nfour\nfive\n"})
+
+	// A fake linter reporting an issue on an untouched line and one on the changed line
+	linters = map[string]string{"*.txt": `sh -c 'for f in "$@"; do echo "$f:1: old issue"; echo "$f:3:7: new issue"; done' sh`}
+	t.Cleanup(func() { linters = map[string]string{} })
+
+	got, err := RunLinter(context.Background(), json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("RunLinter() error = %v", err)
+	}
+	if !strings.Contains(got, "Found 1 lint finding(s)") || !strings.Contains(got, "notes.txt:3:7: new issue") {
+		t.Errorf("RunLinter() = %q, want only the finding on the changed line", got)
+	}
+	if strings.Contains(got, "old issue") {
+		t.Errorf("RunLinter() = %q, want the finding on the untouched line dropped", got)
+	}
+}
+
+func TestChangedLines(t *testing.T) {
+	initTestRepo(t)
+	commitFiles(t, "base", map[string]string{"f.txt": "1\n2\n3\n4\n5\n6\n"})
+	writeTree(t, map[string]string{"f.txt": "1\nchanged\n3\n4\nadded\nadded\n5\n"})
+
+	got, err := ChangedLines(context.Background(), "f.txt")
+	if err != nil {
+		t.Fatalf("ChangedLines() error = %v", err)
+	}
+	if want := map[int]bool{2: true, 5: true, 6: true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangedLines() = %v, want %v", got, want)
+	}
+}
+
+func TestFilterLintFindings(t *testing.T) {
+	output := "src/a.go:4:2: unused variable\n./src/a.go:9: shadowed\nsrc/b.go:4: other file\nsummary: 3 issues\n"
+	touched := map[string]map[int]bool{"src/a.go": {4: true, 9: true}}
+
+	got := FilterLintFindings(output, touched)
+	if want := []string{"src/a.go:4:2: unused variable", "./src/a.go:9: shadowed"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterLintFindings() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Matches tokens that suggest a resolution was left unfinished
+var placeholderPattern = regexp.MustCompile(`\b(TODO|FIXME|XXX)\b|(?i:<\s*placeholder[^>]*>|\bresolve (this|conflict) (later|manually)\b)`)
+
+var ScanPlaceholdersDefinition = ToolDefinition{
+	Name:        "scan_placeholders",
+	Description: "Scan a resolved file for TODO/FIXME/XXX or placeholder text that is not present on either side of the merge (index stages 2 and 3). Any hits mean the resolution may have punted instead of actually merging the code.",
+	InputSchema: ScanPlaceholdersInputSchema,
+	Function:    ScanPlaceholders,
+}
+
+type ScanPlaceholdersInput struct {
+	Path string `json:"path" jsonschema_description:"The path to the resolved file to scan"`
+}
+
+var ScanPlaceholdersInputSchema = GenerateSchema[ScanPlaceholdersInput]()
+
+func ScanPlaceholders(ctx context.Context, input json.RawMessage) (string, error) {
+	var params ScanPlaceholdersInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if err := ValidateFileExists(params.Path); err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(params.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// A missing side (e.g. the file was added on one branch) simply contributes no lines
+	ours, oursErr := GetFileVersionAtStage(ctx, params.Path, 2)
+	theirs, theirsErr := GetFileVersionAtStage(ctx, params.Path, 3)
+	if oursErr != nil && theirsErr != nil {
+		return "", fmt.Errorf("no conflict stages found for %s, is the file part of an in-progress merge?", params.Path)
+	}
+
+	original := strings.Join(append(splitSide(ours, oursErr), splitSide(theirs, theirsErr)...), "\n")
+	added := FindNewPlaceholders(original, string(content))
+	if len(added) == 0 {
+		return fmt.Sprintf("No new TODO/FIXME or placeholder text found in %s", params.Path), nil
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Found %d line(s) in %s with placeholder text that neither side of the merge contains:\n\n", len(added), params.Path))
+	for _, line := range added {
+		result.WriteString(fmt.Sprintf("  %s\n", line))
+	}
+	result.WriteString("\nReplace these with a real resolution unless they are intentional.")
+	return result.String(), nil
+}
+
+// FindNewPlaceholders returns the trimmed lines of updated that contain placeholder text and are not
+// already present in original. Each original line only excuses one occurrence in updated.
+func FindNewPlaceholders(original, updated string) []string {
+	existing := make(map[string]int)
+	for _, line := range strings.Split(original, "\n") {
+		if placeholderPattern.MatchString(line) {
+			existing[strings.TrimSpace(line)]++
+		}
+	}
+
+	var added []string
+	for _, line := range strings.Split(updated, "\n") {
+		if !placeholderPattern.MatchString(line) {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if existing[trimmed] > 0 {
+			existing[trimmed]--
+			continue
+		}
+		added = append(added, trimmed)
+	}
+	return added
+}
+
+// placeholderWarning returns a warning to append to a tool result when an edit introduced placeholder text
+func placeholderWarning(path, original, updated string) string {
+	added := FindNewPlaceholders(original, updated)
+	if len(added) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n\nWarning: this edit added TODO/FIXME or placeholder text to %s that was not there before:\n  %s\n"+
+		"Make sure the conflict is actually resolved rather than deferred.", path, strings.Join(added, "\n  "))
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindNewPlaceholders(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		updated  string
+		want     []string
+	}{
+		{
+			name:     "new TODO is flagged",
+			original: "<<<<<<< HEAD\nx := 1\n=======\nx := 2\n>>>>>>> feature\n",
+			updated:  "// TODO: pick the right value\nx := 1\n",
+			want:     []string{"// TODO: pick the right value"},
+		},
+		{
+			name:     "TODO already on a side is not flagged",
+			original: "<<<<<<< HEAD\n\t// TODO: handle errors\nx := 1\n=======\nx := 2\n>>>>>>> feature\n",
+			updated:  "\t// TODO: handle errors\nx := 2\n",
+			want:     nil,
+		},
+		{
+			name:     "duplicated existing TODO is flagged once",
+			original: "// FIXME: slow\n",
+			updated:  "// FIXME: slow\n// FIXME: slow\n",
+			want:     []string{"// FIXME: slow"},
+		},
+		{
+			name:     "no placeholders",
+			original: "a\n",
+			updated:  "b\n",
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindNewPlaceholders(tt.original, tt.updated); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindNewPlaceholders() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var SearchInFileDefinition = ToolDefinition{
+	Name:        "search_in_file",
+	Description: "Search a single file for a pattern and return the matching line numbers and lines, optionally only within a line range. Much cheaper than view_file on large files when you only need to locate something; use search_symbol to search the whole project.",
+	InputSchema: SearchInFileInputSchema,
+	Function:    SearchInFile,
+}
+
+type SearchInFileInput struct {
+	Path          string `json:"path" jsonschema_description:"The path of the file to search"`
+	Pattern       string `json:"pattern" jsonschema_description:"The text to search for, or a regular expression if is_regex is true"`
+	IsRegex       bool   `json:"is_regex,omitempty" jsonschema_description:"Treat pattern as a regular expression instead of literal text"`
+	CaseSensitive bool   `json:"case_sensitive,omitempty" jsonschema_description:"Whether the search is case-sensitive (defaults to false)"`
+	StartLine     int    `json:"start_line,omitempty" jsonschema_description:"Only report matches on or after this 1-based line"`
+	EndLine       int    `json:"end_line,omitempty" jsonschema_description:"Only report matches on or before this 1-based line"`
+}
+
+var SearchInFileInputSchema = GenerateSchema[SearchInFileInput]()
+
+func SearchInFile(ctx context.Context, input json.RawMessage) (string, error) {
+	var params SearchInFileInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if params.Pattern == "" {
+		return "", fmt.Errorf("pattern cannot be empty")
+	}
+	if err := ValidateFileExists(params.Path); err != nil {
+		return "", err
+	}
+	if params.StartLine < 0 || params.EndLine < 0 {
+		return "", fmt.Errorf("start_line and end_line cannot be negative")
+	}
+	if params.EndLine > 0 && params.EndLine < params.StartLine {
+		return "", fmt.Errorf("end_line cannot be less than start_line")
+	}
+
+	pattern := params.Pattern
+	if !params.IsRegex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if !params.CaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid regex pattern: %w", err)
+	}
+
+	matches, err := searchFile(params.Path, re, 0, 0)
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for _, match := range matches {
+		if match.Line < params.StartLine || (params.EndLine > 0 && match.Line > params.EndLine) {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%d: %s", match.Line, truncateLine(match.Content)))
+	}
+
+	scope := params.Path
+	if params.StartLine > 0 || params.EndLine > 0 {
+		end := "end"
+		if params.EndLine > 0 {
+			end = fmt.Sprintf("%d", params.EndLine)
+		}
+		scope = fmt.Sprintf("%s (lines %d-%s)", params.Path, max(params.StartLine, 1), end)
+	}
+
+	if len(lines) == 0 {
+		return fmt.Sprintf("No matches for '%s' in %s", params.Pattern, scope), nil
+	}
+	return fmt.Sprintf("Found %d match(es) for '%s' in %s:\n%s", len(lines), params.Pattern, scope, strings.Join(lines, "\n")), nil
+}
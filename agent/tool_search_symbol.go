@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
@@ -11,15 +12,22 @@ import (
 type SearchSymbolParams struct {
 	// The symbol to search for. Can be a literal string or a regex pattern
 	Symbol string `json:"symbol" jsonschema:"description=The symbol to search for (e.g. function name, class name, variable). Can be a regular expression."`
-	
+
 	// Whether the symbol should be treated as a regex pattern
 	IsRegex bool `json:"is_regex,omitempty" jsonschema:"description=If true, the symbol will be treated as a regular expression pattern."`
-	
-	// Optional glob pattern to filter which files to search in (e.g. "*.go", "src/**/*.ts")
-	FilePattern string `json:"file_pattern,omitempty" jsonschema:"description=Optional glob pattern to filter which files to search in (e.g. '*.go', 'src/**/*.ts')."`
-	
+
+	// Optional glob patterns to filter which files to search in (e.g. "*.go", "src/**/*.ts")
+	FilePattern string `json:"file_pattern,omitempty" jsonschema:"description=Optional glob patterns to filter which files to search in (e.g. '*.go' or 'src/**/*.ts'). Separate several patterns with commas. Patterns with a slash match the path from the repository root and ** matches any number of directories."`
+
+	// Optional glob patterns for files to skip
+	ExcludePattern string `json:"exclude_pattern,omitempty" jsonschema:"description=Optional comma-separated glob patterns for files to skip (e.g. '*_test.go')."`
+
 	// Whether the search should be case-sensitive
 	CaseSensitive bool `json:"case_sensitive,omitempty" jsonschema:"description=Whether the search should be case-sensitive. Defaults to false."`
+
+	// Number of lines to show around each match
+	ContextBefore int `json:"context_before,omitempty" jsonschema:"description=Number of lines to include before each match. Defaults to 0."`
+	ContextAfter  int `json:"context_after,omitempty" jsonschema:"description=Number of lines to include after each match. Defaults to 0. Use this to read a whole function body in one call."`
 }
 
 var SearchSymbolDefinition = ToolDefinition{
@@ -28,9 +36,10 @@ var SearchSymbolDefinition = ToolDefinition{
 - Can search using literal strings or regular expressions
 - Optionally filter files by glob pattern
 - Returns matching lines with file paths and line numbers
+- Optionally includes surrounding lines, with the match marked by a '>' gutter
 - Useful for finding declarations and usages of symbols`,
 	InputSchema: GenerateSchema[SearchSymbolParams](),
-	Function: func(input json.RawMessage) (string, error) {
+	Function: func(ctx context.Context, input json.RawMessage) (string, error) {
 		var params SearchSymbolParams
 		if err := json.Unmarshal(input, &params); err != nil {
 			return "", fmt.Errorf("failed to parse search symbol parameters: %w", err)
@@ -39,6 +48,9 @@ var SearchSymbolDefinition = ToolDefinition{
 		if params.Symbol == "" {
 			return "", fmt.Errorf("symbol parameter cannot be empty")
 		}
+		if params.ContextBefore < 0 || params.ContextAfter < 0 {
+			return "", fmt.Errorf("context_before and context_after cannot be negative")
+		}
 
 		// Prepare search pattern
 		searchPattern := params.Symbol
@@ -58,7 +70,7 @@ var SearchSymbolDefinition = ToolDefinition{
 			includePattern = "*" // Default to all files in current directory
 		}
 
-		res, err := grep(searchPattern, includePattern, params.CaseSensitive)
+		res, err := grepWithContext(ctx, searchPattern, includePattern, params.ExcludePattern, params.CaseSensitive, params.ContextBefore, params.ContextAfter)
 		if err != nil {
 			return "", fmt.Errorf("search failed: %w", err)
 		}
@@ -88,6 +100,21 @@ var SearchSymbolDefinition = ToolDefinition{
 				}
 			}
 
+			// With context, show the surrounding lines numbered, marking the match
+			if params.ContextBefore > 0 || params.ContextAfter > 0 {
+				output.WriteString(fmt.Sprintf("%s:\n", relPath))
+				first := match.Line - len(match.Before)
+				for i, line := range match.Before {
+					output.WriteString(fmt.Sprintf("  %d: %s\n", first+i, truncateLine(line)))
+				}
+				output.WriteString(fmt.Sprintf("> %d: %s\n", match.Line, truncateLine(match.Content)))
+				for i, line := range match.After {
+					output.WriteString(fmt.Sprintf("  %d: %s\n", match.Line+1+i, truncateLine(line)))
+				}
+				output.WriteString("\n")
+				continue
+			}
+
 			// Format the line with some context
 			content := strings.TrimSpace(match.Content)
 			if len(content) > 120 { // Truncate very long lines
@@ -99,4 +126,12 @@ var SearchSymbolDefinition = ToolDefinition{
 
 		return output.String(), nil
 	},
-}
\ No newline at end of file
+}
+
+// truncateLine shortens very long lines in search results, keeping their indentation
+func truncateLine(line string) string {
+	if len(line) > 120 {
+		return line[:117] + "..."
+	}
+	return line
+}
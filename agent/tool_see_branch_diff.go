@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Maximum characters of diff returned by see_branch_diff before it falls back to a summary
+const maxBranchDiffChars = 20000
+
+var SeeBranchDiffDefinition = ToolDefinition{
+	Name:        "see_branch_diff",
+	Description: "Show the unified diff between two refs (branches, tags, or commits), optionally limited to one path. Useful for understanding the overall change a branch makes, beyond the conflicting lines. Large diffs are summarized with a diffstat and truncated; narrow them with path.",
+	InputSchema: SeeBranchDiffInputSchema,
+	Function:    SeeBranchDiff,
+}
+
+type SeeBranchDiffInput struct {
+	From string `json:"from" jsonschema_description:"The ref to diff from, e.g. HEAD or main"`
+	To   string `json:"to" jsonschema_description:"The ref to diff to, e.g. MERGE_HEAD or feature-branch"`
+	Path string `json:"path,omitempty" jsonschema_description:"Optional file or directory to limit the diff to"`
+}
+
+var SeeBranchDiffInputSchema = GenerateSchema[SeeBranchDiffInput]()
+
+func SeeBranchDiff(ctx context.Context, input json.RawMessage) (string, error) {
+	var params SeeBranchDiffInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	for _, ref := range []string{params.From, params.To} {
+		if ref == "" || strings.HasPrefix(ref, "-") {
+			return "", fmt.Errorf("from and to must both be refs, got %q", ref)
+		}
+		if _, err := ExecuteGitCommand(ctx, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+			return "", fmt.Errorf("ref %s does not exist", ref)
+		}
+	}
+
+	args := []string{"diff", params.From + ".." + params.To}
+	if params.Path != "" {
+		args = append(args, "--", params.Path)
+	}
+	diff, err := ExecuteGitCommand(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to diff %s..%s: %w", params.From, params.To, err)
+	}
+	if diff == "" {
+		return fmt.Sprintf("No differences between %s and %s", params.From, params.To), nil
+	}
+
+	header := fmt.Sprintf("Diff from %s (-) to %s (+)", params.From, params.To)
+	if params.Path != "" {
+		header += " in " + params.Path
+	}
+	if len(diff) <= maxBranchDiffChars {
+		return fmt.Sprintf("%s:\n\n%s", header, diff), nil
+	}
+
+	// Too large to show whole, so lead with a summary of every file and cut the diff at a line boundary
+	stat, err := ExecuteGitCommand(ctx, append([]string{"diff", "--stat"}, args[1:]...)...)
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize %s..%s: %w", params.From, params.To, err)
+	}
+	truncated := diff[:maxBranchDiffChars]
+	if i := strings.LastIndex(truncated, "\n"); i > 0 {
+		truncated = truncated[:i]
+	}
+	return fmt.Sprintf("%s (%d characters, truncated; pass path to see one file in full).\n\nSummary:\n%s\n\n%s\n... (diff truncated)",
+		header, len(diff), stat, truncated), nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+var SeeConflictDiffDefinition = ToolDefinition{
+	Name:        "see_conflict_diff",
+	Description: "Show what each side changed in a conflicted file relative to the merge base: the diff from the base (index stage 1) to ours (stage 2) and from the base to theirs (stage 3). Seeing each side's intent is often clearer than the raw chunk text for logic-heavy conflicts.",
+	InputSchema: SeeConflictDiffInputSchema,
+	Function:    SeeConflictDiff,
+}
+
+type SeeConflictDiffInput struct {
+	Path string `json:"path" jsonschema_description:"The path to the conflicted file"`
+}
+
+var SeeConflictDiffInputSchema = GenerateSchema[SeeConflictDiffInput]()
+
+func SeeConflictDiff(ctx context.Context, input json.RawMessage) (string, error) {
+	var params SeeConflictDiffInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	if params.Path == "" {
+		return "", fmt.Errorf("file path cannot be empty")
+	}
+
+	stages, err := conflictStages(ctx, params.Path)
+	if err != nil {
+		return "", err
+	}
+	if len(stages) == 0 {
+		return "", fmt.Errorf("%s has no conflict stages in the index, is the file currently conflicted?", params.Path)
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("File: %s\n\n", params.Path))
+
+	// Without a common ancestor both sides added the file, so compare them directly
+	if !stages[1] {
+		result.WriteString("No merge base version: both sides added this file independently.\n\n")
+		if stages[2] && stages[3] {
+			result.WriteString(stageDiff(ctx, params.Path, 2, 3, "Ours (-) vs theirs (+)"))
+		}
+		return result.String(), nil
+	}
+
+	result.WriteString(stageDiff(ctx, params.Path, 1, 2, "Base (-) vs ours (+), what our branch changed"))
+	if !stages[2] {
+		result.WriteString("Our branch deleted this file.\n\n")
+	}
+	result.WriteString(stageDiff(ctx, params.Path, 1, 3, "Base (-) vs theirs (+), what their branch changed"))
+	if !stages[3] {
+		result.WriteString("Their branch deleted this file.\n")
+	}
+	return result.String(), nil
+}
+
+// conflictStages returns the index stages (1 base, 2 ours, 3 theirs) present for a conflicted file
+func conflictStages(ctx context.Context, path string) (map[int]bool, error) {
+	output, err := ExecuteGitCommand(ctx, "ls-files", "-u", "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index stages: %w", err)
+	}
+
+	// Each line is "<mode> <object> <stage>\t<path>"
+	stages := make(map[int]bool)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(strings.SplitN(line, "\t", 2)[0])
+		if len(fields) == 3 {
+			var stage int
+			if _, err := fmt.Sscanf(fields[2], "%d", &stage); err == nil {
+				stages[stage] = true
+			}
+		}
+	}
+	return stages, nil
+}
+
+// stageDiff describes the diff between two index stages of a file under a heading, or "" if
+// either stage is missing
+func stageDiff(ctx context.Context, path string, from, to int, heading string) string {
+	diff, err := ExecuteGitCommand(ctx, "diff", fmt.Sprintf(":%d:%s", from, path), fmt.Sprintf(":%d:%s", to, path))
+	if err != nil {
+		return ""
+	}
+	if diff == "" {
+		return fmt.Sprintf("%s:\n(no changes)\n\n", heading)
+	}
+	return fmt.Sprintf("%s:\n```diff\n%s\n```\n\n", heading, diff)
+}
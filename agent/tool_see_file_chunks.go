@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -9,7 +10,7 @@ import (
 
 var SeeFileChunksDefinition = ToolDefinition{
 	Name:        "see_file_chunks",
-	Description: "View and analyze the conflict chunks in a file. Shows each chunk with its ID, base code, and incoming code.",
+	Description: "View and analyze the conflict chunks in a file. Shows each chunk with its ID, base code (ours), and incoming code (theirs), labeled with the branch or commit each side comes from.",
 	InputSchema: SeeFileChunksInputSchema,
 	Function:    SeeFileChunks,
 }
@@ -20,7 +21,7 @@ type SeeFileChunksInput struct {
 
 var SeeFileChunksInputSchema = GenerateSchema[SeeFileChunksInput]()
 
-func SeeFileChunks(input json.RawMessage) (string, error) {
+func SeeFileChunks(ctx context.Context, input json.RawMessage) (string, error) {
 	var params SeeFileChunksInput
 	if err := json.Unmarshal(input, &params); err != nil {
 		return "", fmt.Errorf("failed to parse parameters: %w", err)
@@ -57,15 +58,42 @@ func SeeFileChunks(input json.RawMessage) (string, error) {
 	result.WriteString(fmt.Sprintf("File: %s\n\n", params.Path))
 	result.WriteString(fmt.Sprintf("Found %d conflict chunks:\n\n", len(chunks)))
 
+	symbols := ExtractTopLevelSymbols(string(content))
+
 	for _, chunk := range chunks {
-		result.WriteString(fmt.Sprintf("Chunk ID: %d (lines %d-%d)\n", 
+		result.WriteString(fmt.Sprintf("Chunk ID: %d (lines %d-%d)\n",
 			chunk.ID, chunk.StartLine, chunk.EndLine))
-		result.WriteString("Base Code:\n")
+		if scope := formatChunkScope(symbols, chunk); scope != "" {
+			result.WriteString(fmt.Sprintf("Scope: %s\n", scope))
+		}
+		result.WriteString(fmt.Sprintf("Base Code (ours: %s):\n", chunk.BaseLabel))
 		result.WriteString(fmt.Sprintf("```\n%s\n```\n\n", chunk.BaseCode))
-		result.WriteString("Incoming Code:\n")
+		result.WriteString(fmt.Sprintf("Incoming Code (theirs: %s):\n", chunk.IncomingLabel))
 		result.WriteString(fmt.Sprintf("```\n%s\n```\n\n", chunk.IncomingCode))
 		result.WriteString("---\n\n")
 	}
 
 	return result.String(), nil
-}
\ No newline at end of file
+}
+
+// formatChunkScope describes the top-level declarations a chunk falls within or spans
+func formatChunkScope(symbols []OutlineSymbol, chunk ConflictChunk) string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, symbol := range SymbolsForRange(symbols, chunk.StartLine, chunk.EndLine) {
+		label := fmt.Sprintf("%s %s", symbol.Kind, symbol.Name)
+		if !seen[label] {
+			seen[label] = true
+			names = append(names, label)
+		}
+	}
+
+	if len(names) > 1 {
+		return "spans " + strings.Join(names, ", ")
+	}
+	// Between declarations of a file that has them, e.g. among imports
+	if len(names) == 0 && len(symbols) > 0 {
+		return "top level"
+	}
+	return strings.Join(names, "")
+}
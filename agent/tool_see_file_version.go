@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -19,7 +20,7 @@ type SeeFileVersionInput struct {
 
 var SeeFileVersionInputSchema = GenerateSchema[SeeFileVersionInput]()
 
-func SeeFileVersion(input json.RawMessage) (string, error) {
+func SeeFileVersion(ctx context.Context, input json.RawMessage) (string, error) {
 	var params SeeFileVersionInput
 	if err := json.Unmarshal(input, &params); err != nil {
 		return "", fmt.Errorf("failed to parse parameters: %w", err)
@@ -34,11 +35,11 @@ func SeeFileVersion(input json.RawMessage) (string, error) {
 	}
 
 	// Get the file content at the specified commit
-	content, err := GetFileVersionAtCommit(params.Path, params.CommitID)
+	content, err := GetFileVersionAtCommit(ctx, params.Path, params.CommitID)
 	if err != nil {
 		return "", fmt.Errorf("failed to get file version: %w", err)
 	}
 
-	return fmt.Sprintf("File: %s\nCommit: %s\n\nContents:\n%s", 
+	return fmt.Sprintf("File: %s\nCommit: %s\n\nContents:\n%s",
 		params.Path, params.CommitID, content), nil
-}
\ No newline at end of file
+}
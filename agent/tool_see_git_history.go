@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -20,7 +21,7 @@ type SeeGitHistoryInput struct {
 
 var SeeGitHistoryInputSchema = GenerateSchema[SeeGitHistoryInput]()
 
-func SeeGitHistory(input json.RawMessage) (string, error) {
+func SeeGitHistory(ctx context.Context, input json.RawMessage) (string, error) {
 	var params SeeGitHistoryInput
 	if err := json.Unmarshal(input, &params); err != nil {
 		return "", fmt.Errorf("failed to parse parameters: %w", err)
@@ -34,7 +35,7 @@ func SeeGitHistory(input json.RawMessage) (string, error) {
 	}
 
 	// Get commit history
-	rawHistory, err := GetCommitHistory(params.Path, params.Limit)
+	rawHistory, err := GetCommitHistory(ctx, params.Path, params.Limit)
 	if err != nil {
 		return "", fmt.Errorf("failed to get commit history: %w", err)
 	}
@@ -50,4 +51,4 @@ func SeeGitHistory(input json.RawMessage) (string, error) {
 	}
 
 	return fmt.Sprintf("Git repository history:\n\n%s", formattedHistory), nil
-}
\ No newline at end of file
+}
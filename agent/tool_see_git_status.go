@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 var SeeGitStatusDefinition = ToolDefinition{
@@ -18,12 +20,18 @@ type SeeGitStatusInput struct {
 
 var SeeGitStatusInputSchema = GenerateSchema[SeeGitStatusInput]()
 
-func SeeGitStatus(input json.RawMessage) (string, error) {
-	// Simply run git status and return the output
-	output, err := ExecuteGitCommand("status")
+func SeeGitStatus(ctx context.Context, input json.RawMessage) (string, error) {
+	// Run git status and return the output
+	output, err := ExecuteGitCommand(ctx, "status")
 	if err != nil {
 		return "", fmt.Errorf("failed to run git status: %w", err)
 	}
 
+	// Point out generated files, which should be regenerated rather than merged
+	if generated, err := ListGeneratedConflicts(ctx); err == nil && len(generated) > 0 {
+		output += "\n\nConflicted files that look generated (resolve them with resolve_generated_file instead of merging):\n  " +
+			strings.Join(generated, "\n  ")
+	}
+
 	return output, nil
 }
@@ -2,19 +2,32 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // ConflictChunk represents a git merge conflict chunk
 type ConflictChunk struct {
-	ID           int    `json:"id"`
-	BaseCode     string `json:"base_code"`
-	IncomingCode string `json:"incoming_code"`
-	StartLine    int    `json:"start_line"`
-	EndLine      int    `json:"end_line"`
+	ID            int    `json:"id"`
+	BaseCode      string `json:"base_code"`
+	IncomingCode  string `json:"incoming_code"`
+	BaseLabel     string `json:"base_label"`     // Branch or commit after <<<<<<<, or "ours" if absent
+	IncomingLabel string `json:"incoming_label"` // Branch or commit after >>>>>>>, or "theirs" if absent
+	StartLine     int    `json:"start_line"`
+	EndLine       int    `json:"end_line"`
+}
+
+// markerLabel returns the label following a conflict marker prefix, or fallback if there is none
+func markerLabel(line, prefix, fallback string) string {
+	if label := strings.TrimSpace(strings.TrimPrefix(line, prefix)); label != "" {
+		return label
+	}
+	return fallback
 }
 
 // ValidateFileExists checks if a file exists and returns an error if it doesn't
@@ -29,9 +42,9 @@ func ValidateFileExists(path string) error {
 	return nil
 }
 
-// ExecuteGitCommand runs a git command and returns its output
-func ExecuteGitCommand(args ...string) (string, error) {
-	cmd := exec.Command("git", args...)
+// ExecuteGitCommand runs a git command under ctx and returns its output
+func ExecuteGitCommand(ctx context.Context, args ...string) (string, error) {
+	cmd := toolCommand(ctx, "git", args...)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -44,12 +57,66 @@ func ExecuteGitCommand(args ...string) (string, error) {
 	return strings.TrimSpace(stdout.String()), nil
 }
 
-// FindConflictChunks identifies merge conflict chunks in a file's content
+// Tracking states reported by GetTrackingStatus
+const (
+	TrackingStatusTracked   = "tracked"
+	TrackingStatusUntracked = "untracked"
+	TrackingStatusIgnored   = "ignored"
+)
+
+// GetTrackingStatus reports whether a path is tracked by git, untracked, or ignored
+func GetTrackingStatus(ctx context.Context, path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("file path cannot be empty")
+	}
+
+	if _, err := ExecuteGitCommand(ctx, "ls-files", "--error-unmatch", "--", path); err == nil {
+		return TrackingStatusTracked, nil
+	}
+
+	// check-ignore exits non-zero when the path is not ignored
+	if _, err := ExecuteGitCommand(ctx, "check-ignore", "-q", "--", path); err == nil {
+		return TrackingStatusIgnored, nil
+	}
+
+	return TrackingStatusUntracked, nil
+}
+
+// IsMergeInProgress checks if the repository is in the middle of a merge
+func IsMergeInProgress(ctx context.Context) bool {
+	_, err := ExecuteGitCommand(ctx, "rev-parse", "-q", "--verify", "MERGE_HEAD")
+	return err == nil
+}
+
+// ListUnmergedFiles returns the paths git still reports as unmerged
+func ListUnmergedFiles(ctx context.Context) ([]string, error) {
+	output, err := ExecuteGitCommand(ctx, "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, err
+	}
+
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// Sections of a conflict chunk while parsing
+const (
+	outsideConflict = iota
+	inOurs
+	inAncestor // diff3-style "|||||||" section, which is not part of either side
+	inTheirs
+)
+
+// FindConflictChunks identifies merge conflict chunks in a file's content.
+// CRLF line endings are parsed like LF, so chunk code and labels never carry carriage returns.
+// Either side of a chunk may be empty, but every chunk must have its "=======" separator.
 func FindConflictChunks(content string) ([]ConflictChunk, error) {
-	lines := strings.Split(content, "\n")
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
 	var chunks []ConflictChunk
 
-	inConflict := false
+	state := outsideConflict
 	var currentChunk ConflictChunk
 	var baseLines, incomingLines []string
 	currentID := 0
@@ -57,53 +124,80 @@ func FindConflictChunks(content string) ([]ConflictChunk, error) {
 	for i, line := range lines {
 		lineNum := i + 1 // 1-based line numbers
 
-		if strings.HasPrefix(line, "<<<<<<<") {
-			if inConflict {
+		switch {
+		case strings.HasPrefix(line, "<<<<<<<"):
+			if state != outsideConflict {
 				return nil, fmt.Errorf("nested conflict markers found, which is not supported")
 			}
-			inConflict = true
+			state = inOurs
 			currentChunk = ConflictChunk{
 				ID:        currentID,
+				BaseLabel: markerLabel(line, "<<<<<<<", "ours"),
 				StartLine: lineNum,
 			}
-			continue
-		}
+			baseLines, incomingLines = nil, nil
 
-		if inConflict && strings.HasPrefix(line, "=======") {
-			currentChunk.BaseCode = strings.Join(baseLines, "\n")
-			baseLines = nil
-			continue
-		}
+		case state == inOurs && strings.HasPrefix(line, "|||||||"):
+			state = inAncestor
+
+		case (state == inOurs || state == inAncestor) && strings.HasPrefix(line, "======="):
+			state = inTheirs
 
-		if inConflict && strings.HasPrefix(line, ">>>>>>>") {
-			inConflict = false
+		case state != outsideConflict && strings.HasPrefix(line, ">>>>>>>"):
+			if state != inTheirs {
+				return nil, fmt.Errorf("conflict chunk starting at line %d has no ======= separator", currentChunk.StartLine)
+			}
+			state = outsideConflict
+			currentChunk.BaseCode = strings.Join(baseLines, "\n")
 			currentChunk.IncomingCode = strings.Join(incomingLines, "\n")
+			currentChunk.IncomingLabel = markerLabel(line, ">>>>>>>", "theirs")
 			currentChunk.EndLine = lineNum
 			chunks = append(chunks, currentChunk)
-			incomingLines = nil
 			currentID++
-			continue
-		}
 
-		if inConflict {
-			if len(baseLines) == 0 && currentChunk.BaseCode == "" {
-				baseLines = append(baseLines, line)
-			} else if currentChunk.BaseCode != "" {
-				incomingLines = append(incomingLines, line)
-			} else {
-				baseLines = append(baseLines, line)
-			}
+		case state == inOurs:
+			baseLines = append(baseLines, line)
+
+		case state == inTheirs:
+			incomingLines = append(incomingLines, line)
 		}
 	}
 
-	if inConflict {
+	if state != outsideConflict {
 		return nil, fmt.Errorf("unclosed conflict marker found")
 	}
 
 	return chunks, nil
 }
 
-// HasMergeConflicts checks if a file has merge conflicts
+// Conflict marker prefixes that must never appear in a resolution
+var conflictMarkerPrefixes = []string{"<<<<<<<", "|||||||", "=======", ">>>>>>>"}
+
+// FindConflictMarker returns the 1-based number and text of the first line in content that is a
+// conflict marker, or 0 if there is none. Longer runs such as "========" are not markers.
+func FindConflictMarker(content string) (int, string) {
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if isConflictMarker(line) {
+			return i + 1, line
+		}
+	}
+	return 0, ""
+}
+
+// isConflictMarker reports whether a line, without its line ending, is a conflict marker
+func isConflictMarker(line string) bool {
+	for _, prefix := range conflictMarkerPrefixes {
+		if line == prefix || strings.HasPrefix(line, prefix+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// HasMergeConflicts checks if a file has merge conflicts. Only markers at the start of a line
+// count, so "<<<<<<<" inside a string literal or a Markdown "=======" underline is not a conflict.
+// Malformed markers, such as an unclosed chunk, still count so they are not silently left behind.
 func HasMergeConflicts(path string) (bool, error) {
 	if err := ValidateFileExists(path); err != nil {
 		return false, err
@@ -114,7 +208,8 @@ func HasMergeConflicts(path string) (bool, error) {
 		return false, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	return strings.Contains(string(content), "<<<<<<<"), nil
+	chunks, err := FindConflictChunks(string(content))
+	return err != nil || len(chunks) > 0, nil
 }
 
 // ReplaceConflictChunk replaces a specific conflict chunk in a file with new content
@@ -128,7 +223,10 @@ func ReplaceConflictChunk(path string, chunkID int, newContent string) error {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
-	chunks, err := FindConflictChunks(string(content))
+	// Edit with LF line endings and restore the file's own line endings when writing
+	text, eol := toLF(string(content))
+
+	chunks, err := FindConflictChunks(text)
 	if err != nil {
 		return err
 	}
@@ -138,48 +236,138 @@ func ReplaceConflictChunk(path string, chunkID int, newContent string) error {
 	}
 
 	targetChunk := chunks[chunkID]
-	lines := strings.Split(string(content), "\n")
+	lines := strings.Split(text, "\n")
 
 	// Find the start and end of the chunk in the file
 	startLine := targetChunk.StartLine - 1 // Convert back to 0-based index
 	endLine := targetChunk.EndLine - 1     // Convert back to 0-based index
 
-	// Replace the chunk with the new content
+	// Replace the chunk with the new content. Only empty content removes the chunk without leaving a
+	// blank line; a trailing newline ends the last line rather than adding one, so "\n" is one empty line.
+	var contentLines []string
+	if newContent != "" {
+		contentLines = strings.Split(strings.TrimSuffix(newContent, "\n"), "\n")
+	}
 	newLines := []string{}
 	newLines = append(newLines, lines[:startLine]...)
-	newLines = append(newLines, strings.Split(newContent, "\n")...)
+	newLines = append(newLines, contentLines...)
 	newLines = append(newLines, lines[endLine+1:]...)
 
 	// Write the new content back to the file
 	finalContent := strings.Join(newLines, "\n")
-	err = os.WriteFile(path, []byte(finalContent), 0644)
+	snapshotFile(path)
+	err = os.WriteFile(path, []byte(fromLF(finalContent, eol)), 0644)
 	if err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
+	shiftConflictRegions(path, targetChunk.StartLine, targetChunk.EndLine, len(contentLines))
+	recordResolution(path, targetChunk, newContent)
+
 	return nil
 }
 
-// GetFileBlame returns the git blame information for a file
-func GetFileBlame(path string) (string, error) {
+// BlameLine is the commit that last changed one line of a file
+type BlameLine struct {
+	Line   int    // Line number in the current file
+	Commit string // Abbreviated commit hash, all zeros for uncommitted lines
+	Author string
+	Date   string // Author date as YYYY-MM-DD
+}
+
+// GetFileBlame returns the git blame information for lines start..end of a file (1-based and
+// inclusive), or for the whole file when both are 0
+// Author names are canonicalized through .mailmap, which blame applies by default
+func GetFileBlame(ctx context.Context, path string, start, end int) ([]BlameLine, error) {
 	if err := ValidateFileExists(path); err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return ExecuteGitCommand("blame", "-s", path)
+	args := []string{"blame", "--line-porcelain"}
+	if start > 0 && end >= start {
+		args = append(args, "-L", fmt.Sprintf("%d,%d", start, end))
+	}
+	output, err := ExecuteGitCommand(ctx, append(args, "--", path)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseBlamePorcelain(output), nil
+}
+
+// parseBlamePorcelain parses the output of git blame --line-porcelain
+func parseBlamePorcelain(output string) []BlameLine {
+	var blame []BlameLine
+	var current BlameLine
+	var authorTime int64
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			// The line's content ends each entry
+			blame = append(blame, current)
+			current = BlameLine{}
+		case strings.HasPrefix(line, "author "):
+			current.Author = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-time "):
+			if seconds, err := strconv.ParseInt(strings.TrimPrefix(line, "author-time "), 10, 64); err == nil {
+				current.Date = time.Unix(seconds, 0).UTC().Format("2006-01-02")
+				authorTime = seconds
+			}
+		case strings.HasPrefix(line, "author-tz "):
+			// Show the date in the author's own time zone, like git blame does
+			if zone, err := time.Parse("-0700", strings.TrimPrefix(line, "author-tz ")); err == nil {
+				current.Date = time.Unix(authorTime, 0).In(zone.Location()).Format("2006-01-02")
+			}
+		case current.Commit == "":
+			// Header: <commit> <original line> <final line> [<lines in group>]
+			fields := strings.Fields(line)
+			if len(fields) >= 3 && isCommitHash(fields[0]) {
+				current.Commit = fields[0][:8]
+				current.Line, _ = strconv.Atoi(fields[2])
+			}
+		}
+	}
+	return blame
+}
+
+// isCommitHash reports whether s is a full SHA-1 or SHA-256 commit hash
+func isCommitHash(s string) bool {
+	if len(s) != 40 && len(s) != 64 {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatBlameTable formats blame as a compact table with one row per line number
+func FormatBlameTable(blame []BlameLine) string {
+	if len(blame) == 0 {
+		return "(no blame information)"
+	}
+
+	width := len(strconv.Itoa(blame[len(blame)-1].Line))
+	rows := []string{fmt.Sprintf("%*s | commit   | date       | author", width, "#")}
+	for _, line := range blame {
+		rows = append(rows, fmt.Sprintf("%*d | %s | %-10s | %s", width, line.Line, line.Commit, line.Date, line.Author))
+	}
+	return strings.Join(rows, "\n")
 }
 
 // GetCommitHistory returns the commit history for the repository or a specific file
 // limit: maximum number of commits to return (defaults to 15 if <= 0)
 // path: optional file path to filter commits (if empty, shows commits for entire repo)
-func GetCommitHistory(path string, limit int) (string, error) {
+func GetCommitHistory(ctx context.Context, path string, limit int) (string, error) {
 	// Handle default case consistently
 	if limit <= 0 {
 		limit = 15 // Default to 15 commits if not specified or invalid
 	}
 
 	// Build git command with limit
-	args := []string{"log", fmt.Sprintf("--max-count=%d", limit), "--pretty=format:%h|%an|%s", "--name-only"}
+	// %aN (rather than %an) maps author names through .mailmap
+	args := []string{"log", fmt.Sprintf("--max-count=%d", limit), "--pretty=format:%h|%aN|%s", "--name-only"}
 
 	// Add file path filter if provided
 	if path != "" {
@@ -189,11 +377,11 @@ func GetCommitHistory(path string, limit int) (string, error) {
 		args = append(args, path)
 	}
 
-	return ExecuteGitCommand(args...)
+	return ExecuteGitCommand(ctx, args...)
 }
 
 // GetFileVersionAtCommit returns the content of a file at a specific commit
-func GetFileVersionAtCommit(path string, commitID string) (string, error) {
+func GetFileVersionAtCommit(ctx context.Context, path string, commitID string) (string, error) {
 	if path == "" {
 		return "", fmt.Errorf("file path cannot be empty")
 	}
@@ -202,24 +390,99 @@ func GetFileVersionAtCommit(path string, commitID string) (string, error) {
 		return "", fmt.Errorf("commit ID cannot be empty")
 	}
 
-	return ExecuteGitCommand("show", fmt.Sprintf("%s:%s", commitID, path))
+	return ExecuteGitCommand(ctx, "show", fmt.Sprintf("%s:%s", commitID, path))
+}
+
+// GetFileVersionAtStage returns the content of a conflicted file at an index stage
+// (1 is the common ancestor, 2 is ours, 3 is theirs)
+func GetFileVersionAtStage(ctx context.Context, path string, stage int) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("file path cannot be empty")
+	}
+
+	if stage < 1 || stage > 3 {
+		return "", fmt.Errorf("stage must be 1, 2, or 3")
+	}
+
+	return ExecuteGitCommand(ctx, "show", fmt.Sprintf(":%d:%s", stage, path))
+}
+
+// Optional "Name <email>" to author commits as, leaving the committer as the configured git user
+var commitAuthor = ""
+
+// ReadMergeMessage returns the merge message git prepared in .git/MERGE_MSG with comment
+// lines stripped, or an empty string if there is none
+func ReadMergeMessage(ctx context.Context) (string, error) {
+	path, err := ExecuteGitCommand(ctx, "rev-parse", "--git-path", "MERGE_MSG")
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read merge message: %w", err)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), nil
 }
 
 // SaveChanges adds and commits all changes
-func SaveChanges(message string) (string, error) {
+func SaveChanges(ctx context.Context, message string) (string, error) {
 	if message == "" {
 		return "", fmt.Errorf("commit message cannot be empty")
 	}
 
+	return commitAll(ctx, fmt.Sprintf("[GitSynth] %s", message))
+}
+
+// SaveMergeChanges adds and commits all changes using git's prepared merge message, with the
+// GitSynth message as the body. Falls back to SaveChanges when there is no merge message.
+func SaveMergeChanges(ctx context.Context, message string) (string, error) {
+	if message == "" {
+		return "", fmt.Errorf("commit message cannot be empty")
+	}
+
+	mergeMessage, err := ReadMergeMessage(ctx)
+	if err != nil {
+		return "", err
+	}
+	if mergeMessage == "" {
+		return SaveChanges(ctx, message)
+	}
+
+	return commitAll(ctx, fmt.Sprintf("%s\n\n[GitSynth] %s", mergeMessage, message))
+}
+
+// commitAll stages all changes and commits them with the exact message given
+func commitAll(ctx context.Context, commitMessage string) (string, error) {
 	// Add all changes
-	_, err := ExecuteGitCommand("add", ".")
+	_, err := ExecuteGitCommand(ctx, "add", ".")
 	if err != nil {
 		return "", err
 	}
 
-	// Commit with the provided message
-	commitMessage := fmt.Sprintf("[GitSynth] %s", message)
-	return ExecuteGitCommand("commit", "-m", commitMessage)
+	args := []string{"commit", "-m", commitMessage}
+	if commitAuthor != "" {
+		args = append(args, "--author", commitAuthor)
+	}
+	output, err := ExecuteGitCommand(ctx, args...)
+	if err != nil {
+		return "", err
+	}
+
+	// Committed files are safe in git, so restore_file starts over from here
+	clearSnapshots()
+	return output, nil
 }
 
 // FormatCommitHistory formats the raw git log output into a structured format
@@ -268,3 +531,20 @@ func FormatCommitHistory(rawHistory string, showFiles bool) (string, error) {
 
 	return strings.Join(result, "\n\n"), nil
 }
+
+// matchPatternMap returns the value and pattern of the first glob pattern in m matching a path (see matchGlob)
+func matchPatternMap(m map[string]string, path string) (string, string) {
+	// Sort patterns so that the match is deterministic when several apply
+	patterns := make([]string, 0, len(m))
+	for pattern := range m {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	for _, pattern := range patterns {
+		if matchGlob(pattern, path) {
+			return m[pattern], pattern
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindConflictChunks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []ConflictChunk
+		wantErr bool
+	}{
+		{
+			name:    "both sides",
+			content: "a\n<<<<<<< HEAD\nours\n=======\ntheirs\n>>>>>>> feature\nb",
+			want: []ConflictChunk{
+				{ID: 0, BaseCode: "ours", IncomingCode: "theirs", BaseLabel: "HEAD", IncomingLabel: "feature", StartLine: 2, EndLine: 6},
+			},
+		},
+		{
+			name:    "empty ours",
+			content: "a\n<<<<<<< HEAD\n=======\ntheirs1\n>>>>>>> b",
+			want: []ConflictChunk{
+				{ID: 0, BaseCode: "", IncomingCode: "theirs1", BaseLabel: "HEAD", IncomingLabel: "b", StartLine: 2, EndLine: 5},
+			},
+		},
+		{
+			name:    "empty theirs",
+			content: "<<<<<<< HEAD\nours1\nours2\n=======\n>>>>>>> b\n",
+			want: []ConflictChunk{
+				{ID: 0, BaseCode: "ours1\nours2", IncomingCode: "", BaseLabel: "HEAD", IncomingLabel: "b", StartLine: 1, EndLine: 5},
+			},
+		},
+		{
+			name:    "lines do not leak into the next chunk",
+			content: "<<<<<<< HEAD\n=======\nt1\n>>>>>>> b\nmid\n<<<<<<< HEAD\no2\n=======\nt2\n>>>>>>> b",
+			want: []ConflictChunk{
+				{ID: 0, BaseCode: "", IncomingCode: "t1", BaseLabel: "HEAD", IncomingLabel: "b", StartLine: 1, EndLine: 4},
+				{ID: 1, BaseCode: "o2", IncomingCode: "t2", BaseLabel: "HEAD", IncomingLabel: "b", StartLine: 6, EndLine: 10},
+			},
+		},
+		{
+			name:    "diff3 ancestor section is not part of either side",
+			content: "<<<<<<< HEAD\nours\n||||||| base\nancestor\n=======\ntheirs\n>>>>>>> b",
+			want: []ConflictChunk{
+				{ID: 0, BaseCode: "ours", IncomingCode: "theirs", BaseLabel: "HEAD", IncomingLabel: "b", StartLine: 1, EndLine: 7},
+			},
+		},
+		{
+			name:    "crlf",
+			content: "<<<<<<< HEAD\r\nours\r\n=======\r\ntheirs\r\n>>>>>>> b\r\n",
+			want: []ConflictChunk{
+				{ID: 0, BaseCode: "ours", IncomingCode: "theirs", BaseLabel: "HEAD", IncomingLabel: "b", StartLine: 1, EndLine: 5},
+			},
+		},
+		{
+			name:    "missing separator",
+			content: "<<<<<<< HEAD\nours\n>>>>>>> b",
+			wantErr: true,
+		},
+		{
+			name:    "unclosed",
+			content: "<<<<<<< HEAD\nours\n=======\n",
+			wantErr: true,
+		},
+		{
+			name:    "no conflicts",
+			content: "Title\n=======\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindConflictChunks(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FindConflictChunks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("FindConflictChunks() returned %d chunks, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("chunk %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// writeTempFile writes content to a new file in a test's temporary directory and returns its path
+func writeTempFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// readTempFile returns a file's content, failing the test if it cannot be read
+func readTempFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+// initTestRepo creates a git repository on branch main in a temporary directory and makes it
+// the working directory for the rest of the test
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	runGit(t, "init", "-q", "-b", "main")
+	runGit(t, "config", "user.name", "Test")
+	runGit(t, "config", "user.email", "test@example.com")
+	return dir
+}
+
+// runGit runs a git command in the working directory and returns its trimmed output,
+// failing the test if the command fails
+func runGit(t *testing.T, args ...string) string {
+	t.Helper()
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// commitFiles writes the given files to the working directory and commits them
+func commitFiles(t *testing.T, message string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, "add", "--", path)
+	}
+	runGit(t, "commit", "-q", "-m", message)
+}
+
+// mergeWithConflicts commits base on main, changes it to theirs on a feature branch and to ours on
+// main, then merges feature into main, leaving the working tree mid-merge
+func mergeWithConflicts(t *testing.T, base, ours, theirs map[string]string) {
+	t.Helper()
+	commitFiles(t, "base", base)
+	runGit(t, "checkout", "-q", "-b", "feature")
+	commitFiles(t, "theirs", theirs)
+	runGit(t, "checkout", "-q", "main")
+	commitFiles(t, "ours", ours)
+	// The merge is expected to stop with conflicts, so its exit status is ignored
+	exec.Command("git", "merge", "-q", "feature").Run()
+}
+
+const crlfConflict = "a\r\n<<<<<<< HEAD\r\nours1\r\nours2\r\n=======\r\ntheirs1\r\n>>>>>>> feature\r\nz\r\n"
+
+func TestFindConflictChunksCRLF(t *testing.T) {
+	chunks, err := FindConflictChunks(crlfConflict)
+	if err != nil {
+		t.Fatalf("FindConflictChunks() error = %v", err)
+	}
+	want := ConflictChunk{ID: 0, BaseCode: "ours1\nours2", IncomingCode: "theirs1", BaseLabel: "HEAD", IncomingLabel: "feature", StartLine: 2, EndLine: 7}
+	if len(chunks) != 1 || chunks[0] != want {
+		t.Errorf("FindConflictChunks() = %+v, want [%+v]", chunks, want)
+	}
+}
+
+func TestReplaceConflictChunkCRLF(t *testing.T) {
+	tests := []struct {
+		name       string
+		newContent string
+		want       string
+	}{
+		{"LF replacement", "one\ntwo", "a\r\none\r\ntwo\r\nz\r\n"},
+		{"CRLF replacement", "one\r\ntwo", "a\r\none\r\ntwo\r\nz\r\n"},
+		{"empty replacement", "", "a\r\nz\r\n"},
+		{"trailing newline", "one\ntwo\n", "a\r\none\r\ntwo\r\nz\r\n"},
+		{"trailing CRLF", "one\r\n", "a\r\none\r\nz\r\n"},
+		{"single empty line", "\n", "a\r\n\r\nz\r\n"},
+		{"two empty lines", "\n\n", "a\r\n\r\n\r\nz\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTempFile(t, "f.txt", crlfConflict)
+			if err := ReplaceConflictChunk(path, 0, tt.newContent); err != nil {
+				t.Fatalf("ReplaceConflictChunk() error = %v", err)
+			}
+			if got := readTempFile(t, path); got != tt.want {
+				t.Errorf("content = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasMergeConflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"Markdown heading", "Title\n=======\n\nSection\n-------\nText\n", false},
+		{"comment banner", "// ==========\n// Setup\n// ==========\nfunc f() {}\n", false},
+		{"markers inside a string", "s := \"<<<<<<< HEAD\"\nt := \"=======\"\n", false},
+		{"real conflict", "# Title\n<<<<<<< HEAD\nours\n=======\ntheirs\n>>>>>>> b\n", true},
+		{"unclosed conflict", "<<<<<<< HEAD\nours\n=======\ntheirs\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTempFile(t, "README.md", tt.content)
+			got, err := HasMergeConflicts(path)
+			if err != nil {
+				t.Fatalf("HasMergeConflicts() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("HasMergeConflicts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMailmapCollapsesAuthors(t *testing.T) {
+	initTestRepo(t)
+	writeTree(t, map[string]string{"notes.txt": "first\n"})
+	runGit(t, "add", "notes.txt")
+	runGit(t, "-c", "user.name=alex", "-c", "user.email=alex@old.example", "commit", "-q", "-m", "first")
+	writeTree(t, map[string]string{"notes.txt": "first\nsecond\n"})
+	runGit(t, "-c", "user.name=Alex Smith", "-c", "user.email=alex@new.example", "commit", "-q", "-am", "second")
+	writeTree(t, map[string]string{".mailmap": "Alex Smith <alex@new.example> <alex@old.example>\n"})
+
+	blame, err := GetFileBlame(context.Background(), "notes.txt", 0, 0)
+	if err != nil {
+		t.Fatalf("GetFileBlame() error = %v", err)
+	}
+	if len(blame) != 2 {
+		t.Fatalf("GetFileBlame() = %+v, want 2 lines", blame)
+	}
+	for _, line := range blame {
+		if line.Author != "Alex Smith" {
+			t.Errorf("GetFileBlame() line %d author = %q, want %q", line.Line, line.Author, "Alex Smith")
+		}
+	}
+
+	history, err := GetCommitHistory(context.Background(), "notes.txt", 0)
+	if err != nil {
+		t.Fatalf("GetCommitHistory() error = %v", err)
+	}
+	if got := strings.Count(history, "|Alex Smith|"); got != 2 || strings.Contains(history, "|alex|") {
+		t.Errorf("GetCommitHistory() = %q, want both commits attributed to Alex Smith", history)
+	}
+}
+
+func TestParseBlamePorcelainHashFormats(t *testing.T) {
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{"SHA-1", strings.Repeat("a1", 20)},
+		{"SHA-256", strings.Repeat("b2", 32)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := tt.hash + " 1 3 1\nauthor Alex Smith\nauthor-time 1700000000\nauthor-tz +0000\nfilename notes.txt\n\tfirst\n"
+			blame := parseBlamePorcelain(output)
+			if len(blame) != 1 {
+				t.Fatalf("parseBlamePorcelain() = %+v, want 1 line", blame)
+			}
+			if blame[0].Commit != tt.hash[:8] || blame[0].Line != 3 {
+				t.Errorf("parseBlamePorcelain() = %+v, want commit %q on line 3", blame[0], tt.hash[:8])
+			}
+		})
+	}
+}
+
+func TestSaveChangesSplitsAuthorAndCommitter(t *testing.T) {
+	initTestRepo(t)
+	commitFiles(t, "base", map[string]string{"f.txt": "base\n"})
+	writeTree(t, map[string]string{"f.txt": "changed\n"})
+
+	commitAuthor = "Pat Author <pat@example.com>"
+	t.Cleanup(func() { commitAuthor = "" })
+	t.Setenv("GIT_COMMITTER_NAME", "GitSynth Bot")
+	t.Setenv("GIT_COMMITTER_EMAIL", "bot@example.com")
+
+	if _, err := SaveChanges(context.Background(), "resolve conflicts"); err != nil {
+		t.Fatalf("SaveChanges() error = %v", err)
+	}
+	got := runGit(t, "log", "-1", "--format=%an <%ae>|%cn <%ce>")
+	if want := "Pat Author <pat@example.com>|GitSynth Bot <bot@example.com>"; got != want {
+		t.Errorf("author|committer = %q, want %q", got, want)
+	}
+}
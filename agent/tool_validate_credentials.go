@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// Timeout for the credential preflight request
+const credentialCheckTimeout = 30 * time.Second
+
+// NewValidateCredentialsDefinition creates the validate_credentials tool bound to a client
+func NewValidateCredentialsDefinition(client *anthropic.Client, model anthropic.Model) ToolDefinition {
+	return ToolDefinition{
+		Name:        "validate_credentials",
+		Description: "Check that the configured Anthropic API key works and the model is accessible by making a minimal request.",
+		InputSchema: ValidateCredentialsInputSchema,
+		Function: func(ctx context.Context, input json.RawMessage) (string, error) {
+			if err := ValidateCredentials(ctx, client, model); err != nil {
+				return "", err
+			}
+			return "API key is valid and the model is accessible", nil
+		},
+	}
+}
+
+type ValidateCredentialsInput struct {
+	// No parameters needed for this tool
+}
+
+var ValidateCredentialsInputSchema = GenerateSchema[ValidateCredentialsInput]()
+
+// ValidateCredentials makes a tiny Messages request to confirm the API key and model access
+func ValidateCredentials(ctx context.Context, client *anthropic.Client, model anthropic.Model) error {
+	ctx, cancel := context.WithTimeout(ctx, credentialCheckTimeout)
+	defer cancel()
+
+	_, err := client.Messages.New(ctx, anthropic.MessageNewParams{
+		Model:     model,
+		MaxTokens: int64(1),
+		Messages: []anthropic.MessageParam{
+			anthropic.NewUserMessage(anthropic.NewTextBlock("ping")),
+		},
+	})
+	if err == nil {
+		return nil
+	}
+
+	var apiErr *anthropic.Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusUnauthorized:
+			return fmt.Errorf("the Anthropic API key was rejected, please provide a valid one using the -api-key flag: %w", err)
+		case http.StatusForbidden:
+			return fmt.Errorf("the Anthropic API key does not have access to the model %s: %w", model, err)
+		case http.StatusNotFound:
+			return fmt.Errorf("the model %s is not available to this API key: %w", model, err)
+		case http.StatusTooManyRequests:
+			return fmt.Errorf("the Anthropic API key is rate limited or out of quota: %w", err)
+		}
+	}
+	return fmt.Errorf("failed to reach the Anthropic API: %w", err)
+}
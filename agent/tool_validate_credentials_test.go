@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+)
+
+// newCredentialsClient returns a client using apiKey against a stub server that accepts only "valid-key"
+func newCredentialsClient(t *testing.T, apiKey string) *anthropic.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("X-Api-Key") != "valid-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`)
+			return
+		}
+		io.WriteString(w, `{"id":"msg_1","type":"message","role":"assistant","model":"stub","content":[{"type":"text","text":"p"}],"stop_reason":"max_tokens","usage":{"input_tokens":1,"output_tokens":1}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := anthropic.NewClient(
+		option.WithAPIKey(apiKey),
+		option.WithBaseURL(server.URL),
+		option.WithMaxRetries(0),
+	)
+	return &client
+}
+
+func TestValidateCredentials(t *testing.T) {
+	if err := ValidateCredentials(context.Background(), newCredentialsClient(t, "valid-key"), "stub"); err != nil {
+		t.Errorf("ValidateCredentials(valid key) error = %v", err)
+	}
+
+	err := ValidateCredentials(context.Background(), newCredentialsClient(t, "wrong-key"), "stub")
+	if err == nil || !strings.Contains(err.Error(), "API key was rejected") {
+		t.Errorf("ValidateCredentials(wrong key) error = %v, want the rejected key error", err)
+	}
+	if code := exitCodeFor(err, nil); code != ExitConfigError {
+		t.Errorf("exitCodeFor(rejected key) = %d, want %d", code, ExitConfigError)
+	}
+}
+
+func TestValidateCredentialsTool(t *testing.T) {
+	tool := NewValidateCredentialsDefinition(newCredentialsClient(t, "valid-key"), "stub")
+	got, err := tool.Function(context.Background(), nil)
+	if err != nil || !strings.Contains(got, "API key is valid") {
+		t.Errorf("validate_credentials = %q, %v, want the key reported valid", got, err)
+	}
+}
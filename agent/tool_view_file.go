@@ -1,15 +1,22 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 )
 
+// Default number of lines above which view_file shows only the start and end of a whole file
+const defaultViewFileMaxLines = 2000
+
+// Number of lines above which view_file elides the middle of a whole file (0 disables the cap)
+var viewFileMaxLines = defaultViewFileMaxLines
+
 var ViewFileDefinition = ToolDefinition{
 	Name:        "view_file",
-	Description: "View the contents of a file with line numbers (shown by default). Optionally includes git blame information to see who edited each line. You can disable line numbers by setting with_line_numbers to false.",
+	Description: "View the contents of a file with line numbers (shown by default). Optionally includes git blame information to see who edited each line. You can disable line numbers by setting with_line_numbers to false. Set start_line and end_line to view only part of a large file, e.g. the region around a conflict chunk.",
 	InputSchema: ViewFileInputSchema,
 	Function:    ViewFile,
 }
@@ -18,11 +25,13 @@ type ViewFileInput struct {
 	Path            string `json:"path" jsonschema_description:"The path to the file to view"`
 	WithBlame       bool   `json:"with_blame,omitempty" jsonschema_description:"Whether to include git blame information (who edited each line)"`
 	WithLineNumbers *bool  `json:"with_line_numbers,omitempty" jsonschema_description:"Whether to display line numbers at the beginning of each line (defaults to true unless explicitly set to false)"`
+	StartLine       int    `json:"start_line,omitempty" jsonschema_description:"First line to show (1-based, defaults to the start of the file)"`
+	EndLine         int    `json:"end_line,omitempty" jsonschema_description:"Last line to show (1-based and inclusive, defaults to the end of the file)"`
 }
 
 var ViewFileInputSchema = GenerateSchema[ViewFileInput]()
 
-func ViewFile(input json.RawMessage) (string, error) {
+func ViewFile(ctx context.Context, input json.RawMessage) (string, error) {
 	var params ViewFileInput
 	if err := json.Unmarshal(input, &params); err != nil {
 		return "", fmt.Errorf("failed to parse parameters: %w", err)
@@ -38,46 +47,125 @@ func ViewFile(input json.RawMessage) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to read file: %w", err)
 	}
+	if err := checkNotBinary(params.Path, content); err != nil {
+		return "", err
+	}
+
+	// Restrict the output to the requested range, keeping the original line numbers
+	lines := strings.Split(string(content), "\n")
+	startLine, endLine, err := viewRange(params.StartLine, params.EndLine, len(lines))
+	if err != nil {
+		return "", err
+	}
+	ranged := startLine > 1 || endLine < len(lines)
 
-	// Process content to add line numbers unless explicitly disabled
-	fileContent := string(content)
-	
 	// Only skip line numbers if WithLineNumbers is explicitly set to false
 	shouldShowLineNumbers := true
 	if params.WithLineNumbers != nil && *params.WithLineNumbers == false {
 		shouldShowLineNumbers = false
 	}
-	
+
+	// Show only the start and end of huge files unless a range was asked for
+	headEnd, tailStart := endLine, endLine+1
+	elided := !ranged && viewFileMaxLines > 0 && len(lines) > viewFileMaxLines
+	if elided {
+		headEnd = viewFileMaxLines * 3 / 4
+		tailStart = len(lines) - (viewFileMaxLines - headEnd) + 1
+	}
+
+	// Process content to add line numbers unless explicitly disabled
+	fileContent := strings.Join(lines[startLine-1:headEnd], "\n")
 	if shouldShowLineNumbers {
-		fileContent = addLineNumbers(fileContent)
+		fileContent = addLineNumbers(fileContent, startLine)
+	}
+	if elided {
+		tailContent := strings.Join(lines[tailStart-1:], "\n")
+		if shouldShowLineNumbers {
+			tailContent = addLineNumbers(tailContent, tailStart)
+		}
+		fileContent += fmt.Sprintf("\n\n... (lines %d-%d of %d omitted; view them with start_line and end_line) ...\n\n%s",
+			headEnd+1, tailStart-1, len(lines), tailContent)
 	}
 
 	// If blame is requested, get git blame and return it along with the content
 	if params.WithBlame {
-		blame, err := GetFileBlame(params.Path)
-		if err != nil {
-			return "", fmt.Errorf("failed to get git blame: %w", err)
+		// Only blame the lines being shown. Git does not count the empty "line" after a final newline.
+		blameStart, blameEnd := 0, 0
+		if ranged || elided {
+			blameStart, blameEnd = startLine, endLine
+			if lines[len(lines)-1] == "" {
+				blameEnd = min(blameEnd, len(lines)-1)
+			}
 		}
-		return fmt.Sprintf("File: %s\n\nContents:\n%s\n\nBlame:\n%s", 
-			params.Path, fileContent, blame), nil
+		var blame []BlameLine
+		if elided {
+			// Blame the two shown parts separately
+			head, err := GetFileBlame(ctx, params.Path, blameStart, headEnd)
+			if err != nil {
+				return "", fmt.Errorf("failed to get git blame: %w", err)
+			}
+			blame = head
+			if tailStart <= blameEnd {
+				tail, err := GetFileBlame(ctx, params.Path, tailStart, blameEnd)
+				if err != nil {
+					return "", fmt.Errorf("failed to get git blame: %w", err)
+				}
+				blame = append(blame, tail...)
+			}
+		} else if blameStart <= blameEnd {
+			blame, err = GetFileBlame(ctx, params.Path, blameStart, blameEnd)
+			if err != nil {
+				return "", fmt.Errorf("failed to get git blame: %w", err)
+			}
+		}
+		return fmt.Sprintf("File: %s%s\n\nContents:\n%s\n\nBlame:\n%s",
+			params.Path, rangeNote(ranged, startLine, endLine, len(lines)), fileContent, FormatBlameTable(blame)), nil
 	}
 
-	return fmt.Sprintf("File: %s\n\nContents:\n%s", params.Path, fileContent), nil
+	return fmt.Sprintf("File: %s%s\n\nContents:\n%s", params.Path, rangeNote(ranged, startLine, endLine, len(lines)), fileContent), nil
+}
+
+// viewRange validates a requested 1-based line range against the file length, filling in defaults
+func viewRange(start, end, total int) (int, int, error) {
+	if start < 0 || end < 0 {
+		return 0, 0, fmt.Errorf("start_line and end_line cannot be negative")
+	}
+	if start == 0 {
+		start = 1
+	}
+	if end == 0 || end > total {
+		end = total
+	}
+	if start > total {
+		return 0, 0, fmt.Errorf("start_line %d is beyond the file length of %d lines", start, total)
+	}
+	if end < start {
+		return 0, 0, fmt.Errorf("end_line cannot be less than start_line")
+	}
+	return start, end, nil
 }
 
-// addLineNumbers adds line numbers at the beginning of each line
-func addLineNumbers(content string) string {
+// rangeNote describes which lines of the file are shown, or is empty for the whole file
+func rangeNote(ranged bool, start, end, total int) string {
+	if !ranged {
+		return ""
+	}
+	return fmt.Sprintf(" (lines %d-%d of %d)", start, end, total)
+}
+
+// addLineNumbers adds line numbers at the beginning of each line, numbering from firstLine
+func addLineNumbers(content string, firstLine int) string {
 	lines := strings.Split(content, "\n")
 	formattedLines := make([]string, len(lines))
-	
-	// Determine width for line number formatting (based on total number of lines)
-	width := len(fmt.Sprintf("%d", len(lines)))
-	
+
+	// Determine width for line number formatting (based on the last line number)
+	width := len(fmt.Sprintf("%d", firstLine+len(lines)-1))
+
 	// Format each line with its line number
 	for i, line := range lines {
-		lineNum := i + 1 // 1-indexed line numbers
+		lineNum := firstLine + i // 1-indexed line numbers
 		formattedLines[i] = fmt.Sprintf("%*d | %s", width, lineNum, line)
 	}
-	
+
 	return strings.Join(formattedLines, "\n")
-}
\ No newline at end of file
+}